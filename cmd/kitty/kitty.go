@@ -1,19 +1,29 @@
 package main
 
 import (
+	"bufio"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/kittycash/kittiverse/src/kitty/generator"
+	"github.com/kittycash/kittiverse/src/kitty/generator/container"
 	"github.com/kittycash/kittiverse/src/kitty/generator/container/v0"
 	"github.com/kittycash/kittiverse/src/kitty/genetics"
 	"github.com/kittycash/kittiverse/src/kitty/graphics"
+	"github.com/kittycash/kittiverse/src/kitty/scene"
+	"github.com/kittycash/kittiverse/src/kitty/sheet"
 	"gopkg.in/urfave/cli.v1"
 	"image"
-	"image/png"
+	"image/gif"
+	"io"
 	"io/ioutil"
 	"log"
+	"math/rand"
 	"os"
+	"path"
+	"strconv"
 	"strings"
+	"text/tabwriter"
 )
 
 var app = cli.NewApp()
@@ -123,7 +133,7 @@ func init() {
 						}
 						dst, dstConfig := layer.RemoveWhitespace(src)
 						return createImage(dstName, dst, func(fn string) error {
-							cName := strings.TrimSuffix(fn, ".png") + ".json"
+							cName := trimExt(fn) + ".json"
 							cData, e := json.MarshalIndent(dstConfig, "", "    ")
 							if e != nil {
 								return e
@@ -169,7 +179,7 @@ func init() {
 							placement = new(layer.Placement)
 						)
 						src, e := openImage(srcName, func(fn string) error {
-							fn = strings.TrimSuffix(fn, ".png") + ".json"
+							fn = trimExt(fn) + ".json"
 
 							data, e := ioutil.ReadFile(fn)
 							if e != nil {
@@ -209,14 +219,26 @@ func init() {
 							Usage: "path of output file",
 							Value: "file.kcg",
 						},
+						cli.StringFlag{
+							Name:  "manifest, m",
+							Usage: "path of JSON or YAML manifest listing the files to compile (instead of 'dir')",
+						},
+						cli.BoolFlag{
+							Name:  "strict",
+							Usage: "fails if any errors are found in the loose files (see 'admin lint')",
+						},
 					},
 					Action: func(ctx *cli.Context) error {
 						gen := generator.NewInstance(
 							v0.NewImagesContainer(),
 							v0.NewLayersContainer(),
 						)
-						if e := gen.Compile(ctx.String("dir")); e != nil {
-							return e
+						report, e := compileInstance(gen, ctx.String("dir"), ctx.String("manifest"), ctx.Bool("strict"))
+						if e != nil {
+							if report.Count(container.SeverityError) > 0 {
+								report.WriteText(os.Stderr)
+							}
+							return cli.NewExitError(e.Error(), 1)
 						}
 						log.Println("[ALLELE_RANGES]", gen.GetAlleleRanges().String(true))
 						f, e := os.Create(ctx.String("output"))
@@ -226,6 +248,156 @@ func init() {
 						return gen.Export(f)
 					},
 				},
+				cli.Command{
+					Name:      "lint",
+					Usage:     "checks loose files for problems that would break compiled kitties",
+					ArgsUsage: "[dir]",
+					Flags: cli.FlagsByName{
+						cli.StringFlag{
+							Name:  "dir, d",
+							Usage: "path of loose files to check (if not given as an argument)",
+							Value: "kitty_layers",
+						},
+						cli.StringFlag{
+							Name:  "manifest, m",
+							Usage: "path of JSON or YAML manifest listing the files to check (instead of 'dir')",
+						},
+						cli.BoolFlag{
+							Name:  "json",
+							Usage: "outputs the report as JSON",
+						},
+					},
+					Action: func(ctx *cli.Context) error {
+						dir := ctx.String("dir")
+						if ctx.NArg() > 0 {
+							dir = ctx.Args().First()
+						}
+						gen := generator.NewInstance(
+							v0.NewImagesContainer(),
+							v0.NewLayersContainer(),
+						)
+						report, e := compileInstance(gen, dir, ctx.String("manifest"), false)
+						if e != nil {
+							return cli.NewExitError(e.Error(), 1)
+						}
+						if ctx.Bool("json") {
+							out, e := json.MarshalIndent(report, "", "    ")
+							if e != nil {
+								return e
+							}
+							fmt.Println(string(out))
+						} else if e := report.WriteText(os.Stdout); e != nil {
+							return e
+						}
+						if e := report.Err(); e != nil {
+							return cli.NewExitError(e.Error(), 1)
+						}
+						return nil
+					},
+				},
+				cli.Command{
+					Name:  "coverage",
+					Usage: "reports which breed and attribute combinations of each layer type are renderable",
+					Flags: cli.FlagsByName{
+						cli.StringFlag{
+							Name:  "file, f",
+							Usage: "path of '.kcg' file to use",
+							Value: "file.kcg",
+						},
+						cli.StringFlag{
+							Name:  "format",
+							Usage: "format of the report (table, csv or json)",
+							Value: "table",
+						},
+					},
+					Action: func(ctx *cli.Context) error {
+						gen, e := importInstance(ctx.String("file"))
+						if e != nil {
+							return e
+						}
+						coverage := gen.Coverage()
+						switch format := ctx.String("format"); format {
+						case "table":
+							return coverage.WriteTable(os.Stdout)
+						case "csv":
+							return coverage.WriteCSV(os.Stdout)
+						case "json":
+							out, e := json.MarshalIndent(coverage, "", "    ")
+							if e != nil {
+								return e
+							}
+							fmt.Println(string(out))
+							return nil
+						default:
+							return errors.New("invalid report format: " + format)
+						}
+					},
+				},
+				cli.Command{
+					Name:  "gallery",
+					Usage: "renders every attribute of a layer type for every breed on a base kitty",
+					Flags: append(cli.FlagsByName{
+						cli.StringFlag{
+							Name:  "file, f",
+							Usage: "path of '.kcg' file to use",
+							Value: "file.kcg",
+						},
+						cli.StringFlag{
+							Name:  "layer-type, l",
+							Usage: "layer type to render (renders all layer types if not set)",
+						},
+						cli.StringFlag{
+							Name:  "dna, d",
+							Usage: "hex or base58 representation of DNA of the base kitty",
+							Value: baseDNA().Hex(),
+						},
+						cli.StringFlag{
+							Name:  "output, o",
+							Usage: "path of directory to output a gallery image per layer type",
+							Value: "gallery",
+						},
+						cli.IntFlag{
+							Name:  "size, s",
+							Usage: "width and height of each cell of the gallery in pixels",
+							Value: 200,
+						},
+						cli.IntFlag{
+							Name:  "workers",
+							Usage: "number of kitties to render in parallel (defaults to number of CPUs)",
+						},
+					}, formatFlags...),
+					Action: func(ctx *cli.Context) error {
+						gen, e := importInstance(ctx.String("file"))
+						if e != nil {
+							return e
+						}
+						base, e := genetics.ParseDNA(ctx.String("dna"))
+						if e != nil {
+							return e
+						}
+						format, opts, e := getFormat(ctx)
+						if e != nil {
+							return e
+						}
+						layerTypes := gen.GetLayerTypes()
+						if lt := ctx.String("layer-type"); lt != "" {
+							layerTypes = []string{lt}
+						}
+						if e := os.MkdirAll(ctx.String("output"), 0755); e != nil {
+							return e
+						}
+						for _, lt := range layerTypes {
+							img, e := sheet.Gallery(gen, lt, base, ctx.Int("size"), ctx.Int("workers"))
+							if e != nil {
+								return errors.New("failed to render gallery of '" + lt + "': " + e.Error())
+							}
+							if e := createImageAs(path.Join(ctx.String("output"), lt), img, format, opts); e != nil {
+								return e
+							}
+						}
+						return nil
+					},
+				},
 			},
 		},
 		cli.Command{
@@ -241,6 +413,10 @@ func init() {
 							Usage: "path of '.kcg' file to use",
 							Value: "file.kcg",
 						},
+						cli.StringFlag{
+							Name:  "mutations, m",
+							Usage: "path of mutation model JSON file, of which novel alleles are not minted",
+						},
 					},
 					Action: func(ctx *cli.Context) error {
 						gen := generator.NewInstance(
@@ -258,8 +434,15 @@ func init() {
 						if e := f.Close(); e != nil {
 							return e
 						}
-						out, e := json.MarshalIndent(
-							gen.GetAlleleRanges().RandomDNA().Breakdown(), "", "    ")
+						mutations, e := readMutationModel(ctx.String("mutations"))
+						if e != nil {
+							return e
+						}
+						r, e := gen.MintRanges(mutations)
+						if e != nil {
+							return e
+						}
+						out, e := json.MarshalIndent(r.RandomDNA().Breakdown(), "", "    ")
 						if e != nil {
 							return e
 						} else {
@@ -271,12 +454,16 @@ func init() {
 				cli.Command{
 					Name:  "image",
 					Usage: "generates a kitty image from DNA",
-					Flags: cli.FlagsByName{
+					Flags: append(cli.FlagsByName{
 						cli.StringFlag{
 							Name:  "dna, d",
-							Usage: "hex representation of DNA",
+							Usage: "hex or base58 representation of DNA",
 							Value: genetics.DNA{}.Hex(),
 						},
+						cli.StringFlag{
+							Name:  "breakdown, b",
+							Usage: "path of DNA breakdown JSON file to use instead of '--dna'",
+						},
 						cli.StringFlag{
 							Name:  "file, f",
 							Usage: "path of '.kcg' file to use",
@@ -287,37 +474,542 @@ func init() {
 							Usage: "path of the output file of the kitty generated from DNA",
 							Value: "kitty.png",
 						},
+					}, formatFlags...),
+					Action: func(ctx *cli.Context) error {
+						gen, e := importInstance(ctx.String("file"))
+						if e != nil {
+							return e
+						}
+						dna, e := getDNA(ctx)
+						if e != nil {
+							return e
+						}
+						img, e := gen.GenerateKitty(dna)
+						if e != nil {
+							return e
+						}
+						format, opts, e := getFormat(ctx)
+						if e != nil {
+							return e
+						}
+						return createImageAs(ctx.String("output"), img, format, opts)
+					},
+				},
+				cli.Command{
+					Name:  "animate",
+					Usage: "generates an animated kitty GIF from DNA",
+					Flags: cli.FlagsByName{
+						cli.StringFlag{
+							Name:  "dna, d",
+							Usage: "hex or base58 representation of DNA",
+							Value: genetics.DNA{}.Hex(),
+						},
+						cli.StringFlag{
+							Name:  "file, f",
+							Usage: "path of '.kcg' file to use",
+							Value: "file.kcg",
+						},
+						cli.StringFlag{
+							Name:  "output, o",
+							Usage: "path of the output file of the kitty generated from DNA",
+							Value: "kitty.gif",
+						},
+						cli.IntFlag{
+							Name:  "delay",
+							Usage: "delay between frames in 100ths of a second",
+							Value: 10,
+						},
 					},
 					Action: func(ctx *cli.Context) error {
-						gen := generator.NewInstance(
-							v0.NewImagesContainer(),
-							v0.NewLayersContainer(),
-						)
-						f, e := os.Open(ctx.String("file"))
+						gen, e := importInstance(ctx.String("file"))
 						if e != nil {
 							return e
 						}
-						s, _ := f.Stat()
-						if e := gen.Import(f, int(s.Size())); e != nil {
+						dna, e := genetics.ParseDNA(ctx.String("dna"))
+						if e != nil {
 							return e
 						}
-						if e := f.Close(); e != nil {
+						anim, e := gen.GenerateKittyAnimation(dna, ctx.Int("delay"))
+						if e != nil {
 							return e
 						}
-						f, e = os.Create(ctx.String("output"))
+						f, e := os.Create(ctx.String("output"))
 						if e != nil {
 							return e
 						}
 						defer f.Close()
-						dna, e := genetics.NewDNAFromHex(ctx.String("dna"))
+						return gif.EncodeAll(f, anim)
+					},
+				},
+				cli.Command{
+					Name:  "describe",
+					Usage: "describes the breed and attributes expressed by DNA, and the layers used to render them",
+					Flags: cli.FlagsByName{
+						cli.StringFlag{
+							Name:  "dna, d",
+							Usage: "hex or base58 representation of DNA",
+							Value: genetics.DNA{}.Hex(),
+						},
+						cli.StringFlag{
+							Name:  "file, f",
+							Usage: "path of '.kcg' file to use",
+							Value: "file.kcg",
+						},
+						cli.BoolFlag{
+							Name:  "json",
+							Usage: "outputs the phenotype as JSON (including metadata)",
+						},
+					},
+					Action: func(ctx *cli.Context) error {
+						gen, e := importInstance(ctx.String("file"))
 						if e != nil {
 							return e
 						}
-						img, e := gen.GenerateKitty(dna)
+						dna, e := genetics.ParseDNA(ctx.String("dna"))
 						if e != nil {
 							return e
 						}
-						return png.Encode(f, img)
+						p, e := gen.Phenotype(dna)
+						if e != nil {
+							return e
+						}
+						if !ctx.Bool("json") {
+							return writePhenotype(os.Stdout, p)
+						}
+						out, e := json.MarshalIndent(p, "", "    ")
+						if e != nil {
+							return e
+						}
+						fmt.Println(string(out))
+						return nil
+					},
+				},
+				cli.Command{
+					Name:      "breed",
+					Usage:     "breeds offspring of two kitties",
+					ArgsUsage: "<DNA of parent 1> <DNA of parent 2>",
+					Flags: cli.FlagsByName{
+						cli.StringFlag{
+							Name:  "file, f",
+							Usage: "path of '.kcg' file to use",
+							Value: "file.kcg",
+						},
+						cli.StringFlag{
+							Name:  "mutations, m",
+							Usage: "path of mutation model JSON file (no mutation if not specified)",
+						},
+						cli.IntFlag{
+							Name:  "count, n",
+							Usage: "number of offspring",
+							Value: 1,
+						},
+						cli.Int64Flag{
+							Name:  "seed",
+							Usage: "seed of the random source (random if 0)",
+						},
+					},
+					Action: func(ctx *cli.Context) error {
+						if ctx.NArg() != 2 {
+							return errors.New("expected the DNA of two parents")
+						}
+						var parents [2]genetics.DNA
+						for i := range parents {
+							var e error
+							if parents[i], e = genetics.ParseDNA(ctx.Args().Get(i)); e != nil {
+								return errors.New("invalid DNA '" + ctx.Args().Get(i) + "': " + e.Error())
+							}
+						}
+						gen, e := importInstance(ctx.String("file"))
+						if e != nil {
+							return e
+						}
+						mutations, e := readMutationModel(ctx.String("mutations"))
+						if e != nil {
+							return e
+						}
+						b := &genetics.Breeder{
+							Ranges:    gen.GetAlleleRanges(),
+							Mutations: mutations,
+							Dominance: gen.GetDominance(),
+						}
+						if seed := ctx.Int64("seed"); seed != 0 {
+							b.Rand = rand.New(rand.NewSource(seed))
+						}
+						for i := 0; i < ctx.Int("count"); i++ {
+							child, e := b.Breed(parents[0], parents[1])
+							if e != nil {
+								return e
+							}
+							fmt.Println(child.Hex())
+						}
+						return nil
+					},
+				},
+				cli.Command{
+					Name:      "upgrade",
+					Usage:     "converts DNA to the layout of a newer version",
+					ArgsUsage: "[DNA...] (read from stdin, one per line, if not specified)",
+					Flags: cli.FlagsByName{
+						cli.IntFlag{
+							Name:  "version, v",
+							Usage: "version to convert to",
+							Value: int(genetics.DNAVersion),
+						},
+					},
+					Action: func(ctx *cli.Context) error {
+						version := ctx.Int("version")
+						if version < 0 || version > int(genetics.DNAVersion) {
+							return genetics.ErrUnsupportedVersion
+						}
+						var dnas []genetics.DNA
+						if ctx.NArg() == 0 {
+							var e error
+							if dnas, e = readDNAs("-"); e != nil {
+								return e
+							}
+						}
+						for _, arg := range ctx.Args() {
+							dna, e := genetics.ParseDNA(arg)
+							if e != nil {
+								return errors.New("invalid DNA '" + arg + "': " + e.Error())
+							}
+							dnas = append(dnas, dna)
+						}
+						for _, dna := range dnas {
+							out, e := genetics.Upgrade(dna, byte(version))
+							if e != nil {
+								return errors.New("DNA '" + dna.Hex() + "': " + e.Error())
+							}
+							fmt.Println(out.Hex())
+						}
+						return nil
+					},
+				},
+				cli.Command{
+					Name:  "encode",
+					Usage: "converts DNA between its hex and base58 representations",
+					Flags: cli.FlagsByName{
+						cli.StringFlag{
+							Name:  "dna, d",
+							Usage: "hex or base58 representation of DNA ('-' for stdin)",
+							Value: "-",
+						},
+						cli.BoolFlag{
+							Name:  "hex",
+							Usage: "outputs the hex representation (otherwise base58)",
+						},
+					},
+					Action: func(ctx *cli.Context) error {
+						dna, e := readDNA(ctx.String("dna"))
+						if e != nil {
+							return e
+						}
+						if ctx.Bool("hex") {
+							fmt.Println(dna.Hex())
+						} else {
+							fmt.Println(dna.Base58())
+						}
+						return nil
+					},
+				},
+				cli.Command{
+					Name:  "get",
+					Usage: "reads the alleles of a gene of DNA",
+					Flags: cli.FlagsByName{
+						cli.StringFlag{
+							Name:  "dna, d",
+							Usage: "hex or base58 representation of DNA ('-' for stdin)",
+							Value: "-",
+						},
+						cli.StringFlag{
+							Name:  "gene, g",
+							Usage: "name of the gene",
+						},
+						cli.StringFlag{
+							Name:  "slot, s",
+							Usage: "slot of the allele to read (r1, r2 or d), all if not specified",
+						},
+					},
+					Action: func(ctx *cli.Context) error {
+						dna, e := readDNA(ctx.String("dna"))
+						if e != nil {
+							return e
+						}
+						pos, e := getGenePos(ctx.String("gene"))
+						if e != nil {
+							return e
+						}
+						if ctx.String("slot") == "" {
+							b := dna.GetGenotype(pos).Breakdown()
+							fmt.Printf("r1\t%s\nr2\t%s\nd\t%s\n", b.Recessive1, b.Recessive2, b.Dominant)
+							return nil
+						}
+						slot, e := getAlleleSlot(ctx.String("slot"))
+						if e != nil {
+							return e
+						}
+						fmt.Println(dna.GetAllele(pos, slot).Hex())
+						return nil
+					},
+				},
+				cli.Command{
+					Name:  "set",
+					Usage: "modifies an allele of a gene of DNA, and outputs the modified DNA",
+					Flags: cli.FlagsByName{
+						cli.StringFlag{
+							Name:  "dna, d",
+							Usage: "hex or base58 representation of DNA ('-' for stdin)",
+							Value: "-",
+						},
+						cli.StringFlag{
+							Name:  "gene, g",
+							Usage: "name of the gene",
+						},
+						cli.StringFlag{
+							Name:  "slot, s",
+							Usage: "slot of the allele to modify (r1, r2 or d)",
+							Value: genetics.AlleleDominant.String(),
+						},
+						cli.StringFlag{
+							Name:  "allele, a",
+							Usage: "hex representation of the allele",
+						},
+					},
+					Action: func(ctx *cli.Context) error {
+						dna, e := readDNA(ctx.String("dna"))
+						if e != nil {
+							return e
+						}
+						pos, e := getGenePos(ctx.String("gene"))
+						if e != nil {
+							return e
+						}
+						slot, e := getAlleleSlot(ctx.String("slot"))
+						if e != nil {
+							return e
+						}
+						a, e := genetics.NewAlleleFromHex(ctx.String("allele"))
+						if e != nil {
+							return errors.New("invalid allele '" + ctx.String("allele") + "': " + e.Error())
+						}
+						dna.SetAllele(pos, slot, a)
+						fmt.Println(dna.Hex())
+						return nil
+					},
+				},
+				cli.Command{
+					Name:  "build",
+					Usage: "builds DNA from the names of the breed and attributes",
+					Description: "Each gene is specified as '<dominant>[,<r1>[,<r2>]]', where recessive\n" +
+						"   alleles default to the dominant allele. Accessories are named by\n" +
+						"   attribute (or '<slot>/<attribute>'), or 'none'.",
+					Flags: append(cli.FlagsByName{
+						cli.StringFlag{
+							Name:  "file, f",
+							Usage: "path of '.kcg' file to use",
+							Value: "file.kcg",
+						},
+						cli.BoolFlag{
+							Name:  "random, r",
+							Usage: "randomizes genes that are not specified (otherwise they are zero)",
+						},
+						cli.StringFlag{
+							Name:  "mutations, m",
+							Usage: "path of mutation model JSON file, of which novel alleles are not randomized",
+						},
+						cli.BoolFlag{
+							Name:  "breakdown",
+							Usage: "outputs the breakdown of the DNA as JSON",
+						},
+					}, geneFlags()...),
+					Action: func(ctx *cli.Context) error {
+						gen, e := importInstance(ctx.String("file"))
+						if e != nil {
+							return e
+						}
+						spec := generator.DNASpec{
+							Genes:  make(map[string]generator.GeneSpec),
+							Random: ctx.Bool("random"),
+						}
+						for _, pos := range genetics.GenePositions() {
+							if v := ctx.String(pos.String()); v != "" {
+								spec.Genes[pos.String()] = parseGeneSpec(v)
+							}
+						}
+						mutations, e := readMutationModel(ctx.String("mutations"))
+						if e != nil {
+							return e
+						}
+						dna, e := gen.BuildDNA(spec, mutations)
+						if e != nil {
+							return e
+						}
+						if !ctx.Bool("breakdown") {
+							fmt.Println(dna.Hex())
+							return nil
+						}
+						out, e := json.MarshalIndent(dna.Breakdown(), "", "    ")
+						if e != nil {
+							return e
+						}
+						fmt.Println(string(out))
+						return nil
+					},
+				},
+				cli.Command{
+					Name:  "sheet",
+					Usage: "renders kitties of a list of DNAs into a grid (or a sprite atlas)",
+					Flags: append(cli.FlagsByName{
+						cli.StringFlag{
+							Name:  "input, i",
+							Usage: "path of file with a DNA (hex or base58) per line ('-' for stdin)",
+							Value: "-",
+						},
+						cli.StringFlag{
+							Name:  "file, f",
+							Usage: "path of '.kcg' file to use",
+							Value: "file.kcg",
+						},
+						cli.StringFlag{
+							Name:  "output, o",
+							Usage: "path of the output file of the sheet",
+							Value: "sheet.png",
+						},
+						cli.IntFlag{
+							Name:  "columns, c",
+							Usage: "number of columns of the grid",
+							Value: 8,
+						},
+						cli.IntFlag{
+							Name:  "size, s",
+							Usage: "width and height of each cell of the grid in pixels",
+							Value: 256,
+						},
+						cli.StringFlag{
+							Name:  "caption",
+							Usage: "caption below each kitty (none, hex or attributes)",
+							Value: "hex",
+						},
+						cli.StringFlag{
+							Name:  "atlas",
+							Usage: "if set, packs kitties into a sprite atlas and writes its JSON index to this path",
+						},
+						cli.IntFlag{
+							Name:  "atlas-width",
+							Usage: "maximum width of the sprite atlas in pixels",
+							Value: 4096,
+						},
+						cli.IntFlag{
+							Name:  "workers",
+							Usage: "number of kitties to render in parallel (defaults to number of CPUs)",
+						},
+					}, formatFlags...),
+					Action: func(ctx *cli.Context) error {
+						dnas, e := readDNAs(ctx.String("input"))
+						if e != nil {
+							return e
+						}
+						gen, e := importInstance(ctx.String("file"))
+						if e != nil {
+							return e
+						}
+						format, opts, e := getFormat(ctx)
+						if e != nil {
+							return e
+						}
+
+						if atlasName := ctx.String("atlas"); atlasName != "" {
+							img, entries, e := sheet.RenderAtlas(gen, dnas, ctx.Int("atlas-width"), ctx.Int("workers"))
+							if e != nil {
+								return e
+							}
+							data, e := json.MarshalIndent(entries, "", "  ")
+							if e != nil {
+								return e
+							}
+							if e := ioutil.WriteFile(atlasName, data, 0644); e != nil {
+								return e
+							}
+							return createImageAs(ctx.String("output"), img, format, opts)
+						}
+
+						imgs, e := sheet.RenderFitted(gen, dnas, ctx.Int("size"), ctx.Int("workers"))
+						if e != nil {
+							return e
+						}
+						var captions []string
+						switch mode := ctx.String("caption"); mode {
+						case "none":
+						case "hex":
+							for _, dna := range dnas {
+								captions = append(captions, sheet.HexCaption(dna, 16))
+							}
+						case "attributes":
+							for _, dna := range dnas {
+								p, e := gen.Phenotype(dna)
+								if e != nil {
+									return e
+								}
+								captions = append(captions, sheet.AttributeCaption(p))
+							}
+						default:
+							return errors.New("invalid caption mode: " + mode)
+						}
+						img, e := sheet.Sheet(imgs, captions, ctx.Int("columns"), ctx.Int("size"))
+						if e != nil {
+							return e
+						}
+						return createImageAs(ctx.String("output"), img, format, opts)
+					},
+				},
+			},
+		},
+		cli.Command{
+			Name:  "scene",
+			Usage: "tools for composing scenes of kitties",
+			Subcommands: cli.Commands{
+				cli.Command{
+					Name:  "render",
+					Usage: "renders kitties placed on a background as described by a scene config",
+					Flags: append(cli.FlagsByName{
+						cli.StringFlag{
+							Name:  "config, c",
+							Usage: "path of scene config file",
+							Value: "scene.json",
+						},
+						cli.StringFlag{
+							Name:  "file, f",
+							Usage: "path of '.kcg' file to use",
+							Value: "file.kcg",
+						},
+						cli.StringFlag{
+							Name:  "output, o",
+							Usage: "path of the output file of the scene",
+							Value: "scene.png",
+						},
+					}, formatFlags...),
+					Action: func(ctx *cli.Context) error {
+						data, e := ioutil.ReadFile(ctx.String("config"))
+						if e != nil {
+							return e
+						}
+						config := new(scene.Config)
+						if e := json.Unmarshal(data, config); e != nil {
+							return e
+						}
+						gen, e := importInstance(ctx.String("file"))
+						if e != nil {
+							return e
+						}
+						img, e := scene.Render(gen, config)
+						if e != nil {
+							return e
+						}
+						format, opts, e := getFormat(ctx)
+						if e != nil {
+							return e
+						}
+						return createImageAs(ctx.String("output"), img, format, opts)
 					},
 				},
 			},
@@ -349,7 +1041,7 @@ func openImage(srcName string, fnActions ...fnAction) (image.Image, error) {
 	}
 	defer sf.Close()
 
-	src, e := png.Decode(sf)
+	src, e := layer.Decode(sf)
 	if e != nil {
 		return nil, errors.New("failed to decode source: " + e.Error())
 	}
@@ -359,15 +1051,213 @@ func openImage(srcName string, fnActions ...fnAction) (image.Image, error) {
 
 type fnAction func(fn string) error
 
+func importInstance(fileName string) (*generator.Instance, error) {
+	gen := generator.NewInstance(
+		v0.NewImagesContainer(),
+		v0.NewLayersContainer(),
+	)
+	f, e := os.Open(fileName)
+	if e != nil {
+		return nil, e
+	}
+	defer f.Close()
+	s, e := f.Stat()
+	if e != nil {
+		return nil, e
+	}
+	if e := gen.Import(f, int(s.Size())); e != nil {
+		return nil, e
+	}
+	return gen, nil
+}
+
+// readDNA parses the hex or base58 representation of DNA, or reads it from stdin if
+// "-".
+func readDNA(v string) (genetics.DNA, error) {
+	if v != "-" {
+		return genetics.ParseDNA(v)
+	}
+	dnas, e := readDNAs(v)
+	if e != nil {
+		return genetics.DNA{}, e
+	}
+	if len(dnas) != 1 {
+		return genetics.DNA{}, errors.New("expected a single DNA from stdin, got " + strconv.Itoa(len(dnas)))
+	}
+	return dnas[0], nil
+}
+
+// getDNA obtains DNA from the '--breakdown' file if specified, otherwise from
+// '--dna'.
+func getDNA(ctx *cli.Context) (genetics.DNA, error) {
+	if fileName := ctx.String("breakdown"); fileName != "" {
+		data, e := ioutil.ReadFile(fileName)
+		if e != nil {
+			return genetics.DNA{}, e
+		}
+		return genetics.NewDNAFromBreakdownJSON(data)
+	}
+	return genetics.ParseDNA(ctx.String("dna"))
+}
+
+func getGenePos(gene string) (genetics.DNAPos, error) {
+	pos, ok := genetics.NewDNAPosFromString(gene)
+	if !ok {
+		return 0, errors.New("unknown gene '" + gene + "'")
+	}
+	return pos, nil
+}
+
+func getAlleleSlot(v string) (genetics.AlleleSlot, error) {
+	slot, ok := genetics.NewAlleleSlotFromString(v)
+	if !ok {
+		return 0, errors.New("unknown allele slot '" + v + "', expected r1, r2 or d")
+	}
+	return slot, nil
+}
+
+// readDNAs reads DNAs (one per line, hex or base58) from the file ('-' for stdin).
+// Empty lines and lines starting with '#' are ignored.
+func readDNAs(fileName string) ([]genetics.DNA, error) {
+	var r io.Reader = os.Stdin
+	if fileName != "-" {
+		f, e := os.Open(fileName)
+		if e != nil {
+			return nil, e
+		}
+		defer f.Close()
+		r = f
+	}
+	var (
+		dnas    []genetics.DNA
+		scanner = bufio.NewScanner(r)
+	)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		dna, e := genetics.ParseDNA(line)
+		if e != nil {
+			return nil, errors.New("invalid DNA '" + line + "': " + e.Error())
+		}
+		dnas = append(dnas, dna)
+	}
+	return dnas, scanner.Err()
+}
+
+// writePhenotype writes the phenotype as a table of traits.
+func writePhenotype(w io.Writer, p *container.Phenotype) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "dna:\t%s\n", p.Hex)
+	fmt.Fprintf(tw, "base58:\t%s\n", p.Base58)
+	fmt.Fprintf(tw, "version:\t%d\n", p.Version)
+	fmt.Fprintf(tw, "breed:\t%s\n\n", displayName(p.Breed, p.BreedMetadata))
+	fmt.Fprintln(tw, "LAYER TYPE\tATTRIBUTE\tGENE\tALLELE\tLAYER")
+	for _, t := range p.Traits {
+		layerName := t.LayerBreed
+		if t.Fallback() {
+			layerName += " (fallback)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", t.LayerType,
+			displayName(t.Attribute, t.Metadata), t.Gene, t.Allele, layerName)
+	}
+	return tw.Flush()
+}
+
+func displayName(name string, md container.Metadata) string {
+	if md.DisplayName == "" || md.DisplayName == name {
+		return name
+	}
+	return name + " (" + md.DisplayName + ")"
+}
+
+// compileInstance compiles from the manifest file if given, otherwise from
+// the directory of loose files.
+func compileInstance(gen *generator.Instance, dir, manifest string, strict bool) (*container.Report, error) {
+	if manifest != "" {
+		return gen.CompileManifest(manifest, strict)
+	}
+	return gen.CompileWithReport(dir, strict)
+}
+
+// readMutationModel reads the mutation model JSON file, if a file name is
+// given (the model is nil otherwise).
+func readMutationModel(fileName string) (*genetics.MutationModel, error) {
+	if fileName == "" {
+		return nil, nil
+	}
+	data, e := ioutil.ReadFile(fileName)
+	if e != nil {
+		return nil, e
+	}
+	m := new(genetics.MutationModel)
+	if e := json.Unmarshal(data, m); e != nil {
+		return nil, e
+	}
+	return m, nil
+}
+
+// geneFlags returns a flag per gene, for naming its alleles.
+func geneFlags() []cli.Flag {
+	var flags []cli.Flag
+	for _, pos := range genetics.GenePositions() {
+		flags = append(flags, cli.StringFlag{
+			Name:  pos.String(),
+			Usage: "names the alleles of the '" + pos.String() + "' gene",
+		})
+	}
+	return flags
+}
+
+// parseGeneSpec parses '<dominant>[,<r1>[,<r2>]]'.
+func parseGeneSpec(v string) generator.GeneSpec {
+	var (
+		names = strings.SplitN(v, ",", 3)
+		spec  = generator.GeneSpec{Dominant: names[0]}
+	)
+	if len(names) > 1 {
+		spec.Recessive1 = names[1]
+	}
+	if len(names) > 2 {
+		spec.Recessive2 = names[2]
+	}
+	return spec
+}
+
+// baseDNA returns the DNA of the current version with all alleles of zero.
+func baseDNA() genetics.DNA {
+	var dna genetics.DNA
+	dna.SetVersion(genetics.DNAVersion)
+	return dna
+}
+
 func createImage(dstName string, dst image.Image, fnActions ...fnAction) error {
-	if strings.HasSuffix(dstName, ".png") == false {
-		dstName += ".png"
+	return createImageAs(dstName, dst, "", nil, fnActions...)
+}
+
+// createImageAs creates an image file of the given format. If the format is
+// not specified, it is determined by the file extension (defaulting to png).
+// An extension of another format is replaced by that of the given format.
+func createImageAs(dstName string, dst image.Image, format layer.Format, opts *layer.EncodeOptions, fnActions ...fnAction) error {
+	extFormat, ok := layer.FormatFromFileName(dstName)
+	switch {
+	case format == "" && ok:
+		format = extFormat
+	case format == "":
+		format = layer.FormatPNG
+		dstName += format.Ext()
+	case !ok:
+		dstName += format.Ext()
+	case extFormat != format:
+		dstName = trimExt(dstName) + format.Ext()
 	}
 
 	df, e := os.Create(dstName)
 	if e != nil {
 		return errors.New("failed to create image: " + e.Error())
 	}
+	defer df.Close()
 
 	for _, action := range fnActions {
 		if e := action(dstName); e != nil {
@@ -375,5 +1265,38 @@ func createImage(dstName string, dst image.Image, fnActions ...fnAction) error {
 		}
 	}
 
-	return png.Encode(df, dst)
+	return layer.Encode(df, dst, format, opts)
+}
+
+// formatFlags are flags for choosing the format of output images.
+var formatFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "format",
+		Usage: "format of output image (png, jpg, gif or rgba), determined by file extension if not set",
+	},
+	cli.StringFlag{
+		Name:  "background",
+		Usage: "background color as hex (rrggbb) for formats without transparency",
+		Value: "ffffff",
+	},
+}
+
+func getFormat(ctx *cli.Context) (layer.Format, *layer.EncodeOptions, error) {
+	var format layer.Format
+	if name := ctx.String("format"); name != "" {
+		var e error
+		if format, e = layer.ParseFormat(name); e != nil {
+			return "", nil, e
+		}
+	}
+	bg, e := layer.ParseColor(ctx.String("background"))
+	if e != nil {
+		return "", nil, e
+	}
+	return format, &layer.EncodeOptions{Background: bg}, nil
+}
+
+// trimExt removes the file extension of the file name.
+func trimExt(fn string) string {
+	return strings.TrimSuffix(fn, path.Ext(fn))
 }
@@ -0,0 +1,80 @@
+package generator
+
+import (
+	"fmt"
+	"github.com/kittycash/kittiverse/src/kitty/genetics"
+)
+
+// GeneSpec names the attributes of a gene's alleles. The dominant allele
+// (the phenotype) is required, and recessive alleles that are not named are
+// the same as the dominant allele.
+type GeneSpec struct {
+	Recessive1 string `json:"r1,omitempty"`
+	Recessive2 string `json:"r2,omitempty"`
+	Dominant   string `json:"d"`
+}
+
+// DNASpec specifies the genes of a kitty by name (as of genetics.DNAPos). If
+// "Random" is set, genes that are not specified are random (within the mint
+// ranges, see MintRanges), otherwise their alleles are zero.
+type DNASpec struct {
+	Genes  map[string]GeneSpec `json:"genes"`
+	Random bool                `json:"random"`
+}
+
+// GetAllele obtains the allele of the gene that expresses the named attribute
+// (or breed, for the breed gene).
+func (i *Instance) GetAllele(gene, name string) (genetics.Allele, error) {
+	return i.lc.GetAllele(gene, name)
+}
+
+// GetDominance returns the dominance of genes, as used to render kitties.
+func (i *Instance) GetDominance() genetics.DominanceModel {
+	return i.lc.GetDominance()
+}
+
+// MintRanges returns the allele ranges of newly minted kitties, which exclude
+// the novel alleles of the mutation model (if not nil).
+func (i *Instance) MintRanges(m *genetics.MutationModel) (*genetics.AlleleRanges, error) {
+	if m == nil {
+		return i.GetAlleleRanges(), nil
+	}
+	return m.MintRanges(i.GetAlleleRanges())
+}
+
+// BuildDNA builds the DNA of the specified kitty, of which random genes
+// exclude the novel alleles of the mutation model (if not nil).
+func (i *Instance) BuildDNA(spec DNASpec, m *genetics.MutationModel) (genetics.DNA, error) {
+	var dna genetics.DNA
+	if spec.Random {
+		r, e := i.MintRanges(m)
+		if e != nil {
+			return dna, e
+		}
+		dna = r.RandomDNA()
+	} else {
+		dna.SetVersion(genetics.DNAVersion)
+	}
+	for gene, gs := range spec.Genes {
+		pos, ok := genetics.NewDNAPosFromString(gene)
+		if !ok {
+			return dna, fmt.Errorf("gene '%s' does not exist", gene)
+		}
+		if gs.Dominant == "" {
+			return dna, fmt.Errorf("gene '%s': no dominant allele specified", gene)
+		}
+		var alleles [3]genetics.Allele
+		for j, name := range []string{gs.Recessive1, gs.Recessive2, gs.Dominant} {
+			if name == "" {
+				name = gs.Dominant
+			}
+			a, e := i.GetAllele(gene, name)
+			if e != nil {
+				return dna, e
+			}
+			alleles[j] = a
+		}
+		dna.SetGenotype(pos, alleles[0], alleles[1], alleles[2])
+	}
+	return dna, nil
+}
@@ -0,0 +1,104 @@
+package generator
+
+import (
+	"github.com/kittycash/kittiverse/src/kitty/genetics"
+	"testing"
+)
+
+func TestInstance_BuildDNA(t *testing.T) {
+	i := testInstance(t,
+		"eyes/default/b_outline.png",
+		"eyes/default/c_outline.png",
+		"eyes/persian/a_outline.png",
+	)
+	cases := []struct {
+		name    string
+		spec    DNASpec
+		exp     map[genetics.DNAPos][3]uint16 // recessive 1, recessive 2, dominant.
+		expFail bool
+	}{
+		{"empty", DNASpec{}, map[genetics.DNAPos][3]uint16{
+			genetics.DNABreedPos:    {0, 0, 0},
+			genetics.DNAEyesAttrPos: {0, 0, 0},
+		}, false},
+		{"dominant only", DNASpec{Genes: map[string]GeneSpec{
+			"breed": {Dominant: "persian"},
+			"eyes":  {Dominant: "b"},
+		}}, map[genetics.DNAPos][3]uint16{
+			genetics.DNABreedPos:    {1, 1, 1},
+			genetics.DNAEyesAttrPos: {1, 1, 1},
+		}, false},
+		{"recessive", DNASpec{Genes: map[string]GeneSpec{
+			"eyes": {Recessive1: "c", Dominant: "b"},
+		}}, map[genetics.DNAPos][3]uint16{
+			genetics.DNAEyesAttrPos: {2, 1, 1},
+		}, false},
+		{"random", DNASpec{Random: true, Genes: map[string]GeneSpec{
+			"eyes": {Recessive1: "a", Recessive2: "c", Dominant: "b"},
+		}}, map[genetics.DNAPos][3]uint16{
+			genetics.DNAEyesAttrPos: {0, 2, 1},
+		}, false},
+		{"unknown gene", DNASpec{Genes: map[string]GeneSpec{"eyez": {Dominant: "a"}}}, nil, true},
+		{"no dominant", DNASpec{Genes: map[string]GeneSpec{"eyes": {Recessive1: "a"}}}, nil, true},
+		{"unknown attribute", DNASpec{Genes: map[string]GeneSpec{"eyes": {Dominant: "z"}}}, nil, true},
+		{"unknown breed", DNASpec{Genes: map[string]GeneSpec{"breed": {Dominant: "sphynx"}}}, nil, true},
+	}
+	for _, c := range cases {
+		dna, e := i.BuildDNA(c.spec, nil)
+		if c.expFail {
+			if e == nil {
+				t.Errorf("%s: expected error", c.name)
+			}
+			continue
+		}
+		if e != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, e)
+			continue
+		}
+		if dna.Version() != genetics.DNAVersion {
+			t.Errorf("%s: expected version %d, got %d", c.name, genetics.DNAVersion, dna.Version())
+		}
+		for pos, exp := range c.exp {
+			g := dna.GetGenotype(pos)
+			for j, slot := range []genetics.AlleleSlot{
+				genetics.AlleleRecessive1, genetics.AlleleRecessive2, genetics.AlleleDominant} {
+				if a := g.Get(slot); a.Uint16() != exp[j] {
+					t.Errorf("%s: expected allele %s of %s to be %04x, got %04x",
+						c.name, slot, pos, exp[j], a.Uint16())
+				}
+			}
+		}
+		// Built kitties can be rendered.
+		if _, e := i.GenerateKitty(dna); e != nil {
+			t.Errorf("%s: %v", c.name, e)
+		}
+	}
+}
+
+func TestInstance_BuildDNA_Mutations(t *testing.T) {
+	i := testInstance(t,
+		"eyes/default/b_outline.png",
+		"eyes/default/z_outline.png", // rare attribute.
+	)
+	m := &genetics.MutationModel{Genes: map[string]genetics.Mutation{
+		"eyes": {Novel: &genetics.AlleleRange{Min: "0002", Max: "0002"}},
+	}}
+	for j := 0; j < 50; j++ {
+		dna, e := i.BuildDNA(DNASpec{Random: true}, m)
+		if e != nil {
+			t.Fatal(e)
+		}
+		g := dna.GetGenotype(genetics.DNAEyesAttrPos)
+		for _, slot := range []genetics.AlleleSlot{
+			genetics.AlleleRecessive1, genetics.AlleleRecessive2, genetics.AlleleDominant} {
+			if a := g.Get(slot); a.Uint16() == 2 {
+				t.Fatalf("expected no novel eyes allele, got %s", dna.Hex())
+			}
+		}
+	}
+
+	m.Genes["eyes"] = genetics.Mutation{Novel: &genetics.AlleleRange{Min: "0000", Max: "0002"}}
+	if _, e := i.BuildDNA(DNASpec{Random: true}, m); e == nil {
+		t.Error("expected error for novel range without alleles to mint")
+	}
+}
@@ -2,6 +2,7 @@ package container
 
 import (
 	"github.com/kittycash/kittiverse/src/kitty/genetics"
+	"github.com/skycoin/skycoin/src/cipher"
 	"image"
 )
 
@@ -10,6 +11,46 @@ type Layers interface {
 	Import(raw []byte) error
 	Export() []byte
 	Compile(rootDir string, images Images) error
+	CompileWithReport(rootDir string, images Images, report *Report) error
+	CompileManifest(fileName string, images Images, report *Report) error
 	GetAlleleRanges() *genetics.AlleleRanges
 	GenerateKitty(images Images, dna genetics.DNA) (image.Image, error)
+	GenerateKittyFrames(images Images, dna genetics.DNA) ([]image.Image, error)
+	GetBackground(name string) (cipher.SHA256, bool)
+	GetBreedMetadata(breed string) (Metadata, bool)
+	GetAttributeMetadata(layerType, attribute string) (Metadata, bool)
+	GetPhenotype(dna genetics.DNA) (*Phenotype, error)
+	GetLayerTypes() []string
+	GetBreeds() []string
+	GetAttributes(layerType string) ([]string, bool)
+	GetLayerStatus(layerType, breed, attribute string) LayerStatus
+	AttributeDNA(base genetics.DNA, layerType, breed, attribute string) (genetics.DNA, error)
+	GetAllele(gene, name string) (genetics.Allele, error)
+	GetDominance() genetics.DominanceModel
+}
+
+// LayerStatus describes which layer is used to render an attribute of a
+// layer type for a breed.
+type LayerStatus int
+
+const (
+	LayerMissing  LayerStatus = iota // no layer to render.
+	LayerOwn                         // the breed has its own layer.
+	LayerFallback                    // the layer of a fallback breed is used.
+)
+
+// MarshalText implements encoding.TextMarshaler.
+func (s LayerStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s LayerStatus) String() string {
+	switch s {
+	case LayerOwn:
+		return "own"
+	case LayerFallback:
+		return "fallback"
+	default:
+		return "missing"
+	}
 }
@@ -0,0 +1,10 @@
+package container
+
+// Metadata is human-facing information of an attribute or breed.
+type Metadata struct {
+	DisplayName string   `json:"display_name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Rarity      string   `json:"rarity,omitempty"` // rarity tier (i.e. "common", "rare").
+	Artist      string   `json:"artist,omitempty"` // artist credit.
+	Tags        []string `json:"tags,omitempty"`
+}
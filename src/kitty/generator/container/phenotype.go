@@ -0,0 +1,30 @@
+package container
+
+// Phenotype is the breed and attributes expressed by a DNA.
+type Phenotype struct {
+	Hex           string   `json:"hex"`
+	Base58        string   `json:"base58"`
+	Version       byte     `json:"version"`
+	Breed         string   `json:"breed"`
+	BreedMetadata Metadata `json:"breed_metadata"`
+	Traits        []Trait  `json:"traits"`
+}
+
+// Trait is the attribute of a layer type expressed by a DNA (for each render
+// step that draws a layer), and the layer used to render it.
+type Trait struct {
+	LayerType  string      `json:"layer_type"`
+	Gene       string      `json:"gene,omitempty"`      // gene that selects the attribute (if any).
+	Allele     string      `json:"allele,omitempty"`    // hex of the phenotype allele of the gene.
+	Dominance  string      `json:"dominance,omitempty"` // dominance mode of the gene (strict if empty).
+	Attribute  string      `json:"attribute"`
+	LayerBreed string      `json:"layer_breed"` // breed of the layer used.
+	Status     LayerStatus `json:"status"`      // whether the layer is of a fallback breed.
+	Metadata   Metadata    `json:"metadata"`
+}
+
+// Fallback returns whether the trait is rendered with the layer of a fallback
+// breed.
+func (t *Trait) Fallback() bool {
+	return t.Status == LayerFallback
+}
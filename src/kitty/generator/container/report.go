@@ -0,0 +1,113 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Severity is the severity of an issue found when compiling.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // fails a strict compile.
+	SeverityWarning Severity = "warning" // reported only.
+)
+
+// Issue is a problem found within the files being compiled.
+type Issue struct {
+	Severity  Severity `json:"severity"`
+	Path      string   `json:"path,omitempty"`
+	LayerType string   `json:"layer_type,omitempty"`
+	Breed     string   `json:"breed,omitempty"`
+	Attribute string   `json:"attribute,omitempty"`
+	Message   string   `json:"message"`
+}
+
+func (i Issue) String() string {
+	s := string(i.Severity) + ":"
+	if i.Path != "" {
+		s += " " + i.Path + ":"
+	}
+	for _, v := range [][2]string{
+		{"layer_type", i.LayerType},
+		{"breed", i.Breed},
+		{"attribute", i.Attribute},
+	} {
+		if v[1] != "" {
+			s += " " + v[0] + "=" + v[1]
+		}
+	}
+	return s + " " + i.Message
+}
+
+// Report collects the issues found when compiling. A strict report fails the
+// compile if it contains issues of error severity.
+type Report struct {
+	Issues []Issue `json:"issues"`
+	Strict bool    `json:"-"`
+}
+
+// Add adds an issue to the report.
+func (r *Report) Add(issue Issue) {
+	if r == nil {
+		return
+	}
+	r.Issues = append(r.Issues, issue)
+}
+
+// Errorf adds an issue of error severity, with a formatted message.
+func (r *Report) Errorf(issue Issue, format string, a ...interface{}) {
+	issue.Severity, issue.Message = SeverityError, fmt.Sprintf(format, a...)
+	r.Add(issue)
+}
+
+// Warnf adds an issue of warning severity, with a formatted message.
+func (r *Report) Warnf(issue Issue, format string, a ...interface{}) {
+	issue.Severity, issue.Message = SeverityWarning, fmt.Sprintf(format, a...)
+	r.Add(issue)
+}
+
+// Count returns the number of issues of the given severity.
+func (r *Report) Count(severity Severity) int {
+	if r == nil {
+		return 0
+	}
+	var n int
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// Err returns an error if the report contains issues of error severity.
+func (r *Report) Err() error {
+	if n := r.Count(SeverityError); n > 0 {
+		return errors.New("compile failed with " + strconv.Itoa(n) + " error(s)")
+	}
+	return nil
+}
+
+// StrictErr returns the error of Err if the report is strict.
+func (r *Report) StrictErr() error {
+	if r == nil || !r.Strict {
+		return nil
+	}
+	return r.Err()
+}
+
+// WriteText writes the report in human readable form (an issue per line,
+// followed by a summary).
+func (r *Report) WriteText(w io.Writer) error {
+	for _, issue := range r.Issues {
+		if _, e := fmt.Fprintln(w, issue); e != nil {
+			return e
+		}
+	}
+	_, e := fmt.Fprintf(w, "%d error(s), %d warning(s)\n",
+		r.Count(SeverityError), r.Count(SeverityWarning))
+	return e
+}
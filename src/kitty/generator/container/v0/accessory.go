@@ -0,0 +1,174 @@
+package v0
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/kittycash/kittiverse/src/kitty/genetics"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+)
+
+const (
+	PrefixAccessory = "accessory"
+
+	// AccessorySlotsFileName is the name of the optional file (within the root
+	// directory of loose files) that lists the accessory slots in order of
+	// alleles (see Accessory). Slots are in the order of the layer types if
+	// there is no such file, so new slots should be listed last to keep the
+	// alleles of existing accessories.
+	AccessorySlotsFileName = "accessories.json"
+
+	// AccessoryNone is the name of the absence of an accessory.
+	AccessoryNone = "none"
+
+	// AccessoryStride is the number of alleles reserved for each accessory
+	// slot (see Accessory).
+	AccessoryStride = 256
+)
+
+// Accessory represents an attribute of an accessory slot.
+// Accessory slots are layer types with the "accessory_" prefix (i.e.
+// "accessory_hat"). The accessory gene selects a single accessory out of all
+// the accessories of all slots, in which;
+//		1. allele 0 represents no accessory, and
+//		2. allele n*AccessoryStride+i represents the ith attribute of the nth
+//			slot (counting from 1), where slots are ordered as listed in the
+//			accessory slots file (see AccessorySlotsFileName).
+// As each slot has a fixed range of alleles, adding attributes to a slot does
+// not change the alleles of the accessories of other slots. Alleles within a
+// slot's range that are not of an attribute represent no accessory.
+type Accessory struct {
+	Slot      string          `json:"slot"`
+	Attribute string          `json:"attribute"`
+	Allele    genetics.Allele `json:"allele"`
+}
+
+func isAccessorySlot(ltName string) bool {
+	return strings.HasPrefix(ltName, PrefixAccessory+"_")
+}
+
+func (lc *Layers) getAccessorySlots() []*LayersOfType {
+	var out []*LayersOfType
+	for _, slot := range lc.AccessorySlots {
+		if lt, ok := lc.getLayerType(slot); ok {
+			out = append(out, lt)
+		}
+	}
+	return out
+}
+
+// getAccessories returns the accessories of all slots, in order of allele.
+func (lc *Layers) getAccessories() []Accessory {
+	var out []Accessory
+	for n, lt := range lc.getAccessorySlots() {
+		for i, attr := range lt.Attributes {
+			out = append(out, Accessory{
+				Slot:      lt.OfType,
+				Attribute: attr,
+				Allele:    genetics.NewAlleleFromUint16(uint16((n+1)*AccessoryStride + i)),
+			})
+		}
+	}
+	return out
+}
+
+// getAccessory obtains the accessory of the allele. False is returned if the
+// allele represents no accessory.
+func (lc *Layers) getAccessory(a genetics.Allele) (Accessory, bool, error) {
+	var (
+		n     = int(a.Uint16()) / AccessoryStride
+		i     = int(a.Uint16()) % AccessoryStride
+		slots = lc.getAccessorySlots()
+	)
+	switch {
+	case n == 0:
+		return Accessory{}, false, nil
+	case n > len(slots):
+		return Accessory{}, false, errors.New("accessory slot index out of range")
+	case i >= len(slots[n-1].Attributes):
+		return Accessory{}, false, nil
+	}
+	return Accessory{Slot: slots[n-1].OfType, Attribute: slots[n-1].Attributes[i], Allele: a}, true, nil
+}
+
+func initAccessorySlots(lc *Layers, rootDir string) error {
+	data, e := ioutil.ReadFile(path.Join(rootDir, AccessorySlotsFileName))
+	switch {
+	case os.IsNotExist(e):
+		log.Infof("no '%s' file found, ordering accessory slots as layer types", AccessorySlotsFileName)
+		return setAccessorySlots(lc, nil)
+	case e != nil:
+		return e
+	}
+	var slots []string
+	if e := json.Unmarshal(data, &slots); e != nil {
+		return e
+	}
+	return setAccessorySlots(lc, slots)
+}
+
+// setAccessorySlots sets and checks the order of the accessory slots, using
+// the order of the layer types if none is given.
+func setAccessorySlots(lc *Layers, slots []string) error {
+	if slots == nil {
+		for _, lt := range lc.LayerTypes {
+			if isAccessorySlot(lt.OfType) {
+				slots = append(slots, lt.OfType)
+			}
+		}
+	}
+	lc.AccessorySlots = slots
+	return checkAccessorySlots(lc)
+}
+
+// checkAccessorySlots ensures every accessory slot is listed once, and that
+// the accessories of all slots can be represented by alleles.
+func checkAccessorySlots(lc *Layers) error {
+	listed := make(map[string]bool)
+	for _, slot := range lc.AccessorySlots {
+		if !isAccessorySlot(slot) {
+			return fmt.Errorf("accessory slot '%s' is not prefixed with '%s_'", slot, PrefixAccessory)
+		}
+		if _, ok := lc.getLayerType(slot); !ok {
+			return fmt.Errorf("accessory slot '%s' does not exist", slot)
+		}
+		if listed[slot] {
+			return fmt.Errorf("accessory slot '%s' is listed twice", slot)
+		}
+		listed[slot] = true
+	}
+	for _, lt := range lc.LayerTypes {
+		if isAccessorySlot(lt.OfType) && !listed[lt.OfType] {
+			return fmt.Errorf("accessory slot '%s' is not listed", lt.OfType)
+		}
+	}
+	slots := lc.getAccessorySlots()
+	if len(slots) >= AccessoryStride {
+		return fmt.Errorf("%d accessory slots exceed the maximum of %d",
+			len(slots), AccessoryStride-1)
+	}
+	for _, lt := range slots {
+		if len(lt.Attributes) > AccessoryStride {
+			return fmt.Errorf("accessory slot '%s' has %d attributes, exceeding the maximum of %d",
+				lt.OfType, len(lt.Attributes), AccessoryStride)
+		}
+	}
+	return nil
+}
+
+// accessoryRenderSteps returns render steps that draw the accessory slots on
+// top of the kitty, in the order of the slots.
+func accessoryRenderSteps(lc *Layers) []RenderStep {
+	var steps []RenderStep
+	for _, slot := range lc.AccessorySlots {
+		steps = append(steps, RenderStep{
+			Canvas:    CanvasKitty,
+			LayerType: slot,
+			Gene:      genetics.DNAAccessoryPos.String(),
+		})
+	}
+	return steps
+}
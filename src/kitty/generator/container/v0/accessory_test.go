@@ -0,0 +1,177 @@
+package v0
+
+import (
+	"github.com/kittycash/kittiverse/src/kitty/genetics"
+	"reflect"
+	"testing"
+)
+
+func TestLayers_AccessoryAlleles(t *testing.T) {
+	files := testFiles(map[string]string{
+		"accessory_bow/default/a.png": "",
+		"accessory_bow/default/b.png": "",
+		"accessory_hat/default/a.png": "",
+		"accessory_hat/default/c.png": "",
+	})
+	lc, _, _ := compileTestLayers(t, files)
+
+	// A new attribute of the first slot.
+	files["accessory_bow/default/z.png"] = ""
+	lcNew, _, _ := compileTestLayers(t, files)
+
+	cases := []struct {
+		name    string
+		exp     uint16
+		expFail bool
+	}{
+		{AccessoryNone, 0x0000, false},
+		{"b", 0x0101, false},
+		{"accessory_bow/a", 0x0100, false},
+		{"accessory_hat/a", 0x0200, false},
+		{"c", 0x0201, false},
+		{"a", 0, true}, // in multiple slots.
+		{"d", 0, true},
+	}
+	for _, c := range cases {
+		for i, l := range []*Layers{lc, lcNew} {
+			a, e := l.GetAllele(genetics.DNAAccessoryPos.String(), c.name)
+			if c.expFail {
+				if e == nil {
+					t.Errorf("%s (%d): expected error", c.name, i)
+				}
+				continue
+			}
+			if e != nil {
+				t.Fatalf("%s (%d): %v", c.name, i, e)
+			}
+			if a.Uint16() != c.exp {
+				t.Errorf("%s (%d): expected allele %04x, got %04x", c.name, i, c.exp, a.Uint16())
+			}
+		}
+	}
+
+	if a, _ := lcNew.GetAllele(genetics.DNAAccessoryPos.String(), "z"); a.Uint16() != 0x0102 {
+		t.Errorf("z: expected allele 0102, got %04x", a.Uint16())
+	}
+	r, _ := lcNew.GetAlleleRanges().Get(genetics.DNAAccessoryPos)
+	if min, max := r.GetRange(); min != 0 || max != 0x0201 {
+		t.Errorf("expected accessory range [0000, 0201], got [%04x, %04x]", min, max)
+	}
+}
+
+func TestLayers_GetAccessory(t *testing.T) {
+	lc, _, _ := compileTestLayers(t, testFiles(map[string]string{
+		"accessory_bow/default/a.png": "",
+		"accessory_hat/default/a.png": "",
+		"accessory_hat/default/c.png": "",
+	}))
+	cases := []struct {
+		allele  uint16
+		exp     Accessory
+		expOK   bool
+		expFail bool
+	}{
+		{0x0000, Accessory{}, false, false},
+		{0x0005, Accessory{}, false, false},
+		{0x0100, Accessory{Slot: "accessory_bow", Attribute: "a"}, true, false},
+		{0x0101, Accessory{}, false, false}, // no attribute within slot.
+		{0x0201, Accessory{Slot: "accessory_hat", Attribute: "c"}, true, false},
+		{0x0300, Accessory{}, false, true},
+	}
+	for _, c := range cases {
+		a := genetics.NewAlleleFromUint16(c.allele)
+		acc, ok, e := lc.getAccessory(a)
+		if (e != nil) != c.expFail {
+			t.Errorf("%04x: expected failure %v, got error %v", c.allele, c.expFail, e)
+			continue
+		}
+		if ok != c.expOK {
+			t.Errorf("%04x: expected ok %v, got %v", c.allele, c.expOK, ok)
+			continue
+		}
+		if c.expOK {
+			c.exp.Allele = a
+		}
+		if acc != c.exp {
+			t.Errorf("%04x: expected %v, got %v", c.allele, c.exp, acc)
+		}
+	}
+}
+
+func TestLayers_AccessorySlots(t *testing.T) {
+	files := testFiles(map[string]string{
+		"accessory_hat/default/a.png": "",
+		"accessory_bow/default/a.png": "",
+		AccessorySlotsFileName:        `["accessory_hat", "accessory_bow"]`,
+	})
+	lc, _, _ := compileTestLayers(t, files)
+
+	// A new slot listed last keeps the alleles of existing slots, even though
+	// its layer type is ordered first.
+	files["accessory_apron/default/a.png"] = ""
+	files[AccessorySlotsFileName] = `["accessory_hat", "accessory_bow", "accessory_apron"]`
+	lcNew, _, _ := compileTestLayers(t, files)
+
+	for i, l := range []*Layers{lc, lcNew} {
+		for name, exp := range map[string]uint16{
+			"accessory_hat/a": 0x0100,
+			"accessory_bow/a": 0x0200,
+		} {
+			if a, e := l.GetAllele(genetics.DNAAccessoryPos.String(), name); e != nil || a.Uint16() != exp {
+				t.Errorf("%s (%d): expected allele %04x, got %04x (%v)", name, i, exp, a.Uint16(), e)
+			}
+		}
+	}
+
+	// The order is kept by the generation file.
+	imported := NewLayersContainer()
+	if e := imported.Import(lcNew.Export()); e != nil {
+		t.Fatal(e)
+	}
+	if !reflect.DeepEqual(imported.AccessorySlots, lcNew.AccessorySlots) {
+		t.Errorf("expected imported slots %v, got %v", lcNew.AccessorySlots, imported.AccessorySlots)
+	}
+
+	cases := []struct {
+		name  string
+		slots string
+	}{
+		{"unlisted slot", `["accessory_hat"]`},
+		{"slot listed twice", `["accessory_hat", "accessory_bow", "accessory_hat"]`},
+		{"unknown slot", `["accessory_hat", "accessory_bow", "accessory_cape"]`},
+		{"not a slot", `["accessory_hat", "accessory_bow", "eyes"]`},
+	}
+	for _, c := range cases {
+		files := testFiles(map[string]string{
+			"accessory_hat/default/a.png": "",
+			"accessory_bow/default/a.png": "",
+			AccessorySlotsFileName:        c.slots,
+		})
+		if e := NewLayersContainer().Compile(writeTestFiles(t, files), NewImagesContainer()); e == nil {
+			t.Errorf("%s: expected error", c.name)
+		}
+	}
+}
+
+func TestLayers_GetAlleleRanges_Accessory(t *testing.T) {
+	lc, _, _ := compileTestLayers(t, testFiles(map[string]string{
+		"accessory_bow/default/a.png": "",
+		"accessory_bow/default/b.png": "",
+		"accessory_hat/default/a.png": "",
+	}))
+	r, _ := lc.GetAlleleRanges().Get(genetics.DNAAccessoryPos)
+	if exp := []string{"0000", "0100", "0101", "0200"}; !reflect.DeepEqual(r.Values, exp) {
+		t.Fatalf("expected accessory alleles %v, got %v", exp, r.Values)
+	}
+
+	// Minted kitties have no accessory, or an accessory of a slot.
+	for i := 0; i < 100; i++ {
+		dna := lc.GetAlleleRanges().RandomDNA()
+		for _, slot := range []genetics.AlleleSlot{genetics.AlleleRecessive1, genetics.AlleleRecessive2, genetics.AlleleDominant} {
+			a := dna.GetAllele(genetics.DNAAccessoryPos, slot)
+			if _, ok, e := lc.getAccessory(a); e != nil || (!ok && a.Uint16() != 0) {
+				t.Fatalf("minted accessory allele %04x is of no accessory", a.Uint16())
+			}
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package v0
+
+import (
+	"bytes"
+	"github.com/kittycash/kittiverse/src/kitty/generator/container"
+	"github.com/kittycash/kittiverse/src/kitty/generator/container/common"
+	"github.com/skycoin/skycoin/src/cipher"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+)
+
+const (
+	// BackgroundsDirName is the name of the directory (within the root
+	// directory of loose files) that contains background images for scenes.
+	// It is not a layer type.
+	BackgroundsDirName = "backgrounds"
+)
+
+// Background is a named background image used for composing scenes.
+type Background struct {
+	Name  string
+	Image cipher.SHA256
+}
+
+func (lc *Layers) GetBackground(name string) (cipher.SHA256, bool) {
+	for _, bg := range lc.Backgrounds {
+		if bg.Name == name {
+			return bg.Image, true
+		}
+	}
+	return common.EmptyHash(), false
+}
+
+/*
+	<<< HELPERS >>>
+*/
+
+func initBackgrounds(lc *Layers, rootDir string, images container.Images, report *container.Report) error {
+	files, e := ioutil.ReadDir(path.Join(rootDir, BackgroundsDirName))
+	switch {
+	case os.IsNotExist(e):
+		return nil
+	case e != nil:
+		return e
+	}
+	for _, file := range files {
+		if file.IsDir() || strings.HasSuffix(file.Name(), ".png") == false {
+			continue
+		}
+		name := strings.TrimSuffix(file.Name(), ".png")
+		fullPath := path.Join(rootDir, BackgroundsDirName, file.Name())
+		if e := addBackground(lc, images, report, name, fullPath); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// addBackground reads the image file of the named background, and adds it if
+// it can be decoded.
+func addBackground(lc *Layers, images container.Images, report *container.Report, name, fullPath string) error {
+	imgRaw, e := common.GetRawImageFromFile(fullPath)
+	if e != nil {
+		return e
+	}
+	if _, e := png.DecodeConfig(bytes.NewReader(imgRaw)); e != nil {
+		report.Errorf(container.Issue{Path: fullPath}, "failed to decode background image: %v", e)
+		return nil
+	}
+	lc.Backgrounds = append(lc.Backgrounds, Background{
+		Name:  name,
+		Image: images.GetOrAdd(imgRaw),
+	})
+	return nil
+}
@@ -1,11 +1,132 @@
 package v0
 
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/kittycash/kittiverse/src/kitty/generator/container"
+	"github.com/kittycash/kittiverse/src/kitty/graphics"
+	"io/ioutil"
+	"os"
+	"path"
+)
+
+const (
+	// BreedsFileName is the name of the optional file (within the root
+	// directory of loose files) that contains the breed configurations.
+	BreedsFileName = "breeds.json"
+)
+
+// LayerShift is the transformation applied to layers of a layer type when a
+// breed falls back to the layer of another breed.
 type LayerShift struct {
+	LayerType      string               `json:"layer_type"`
+	Transformation layer.Transformation `json:"transformation"`
 }
 
 type LayerTypeName string
 
+// BreedConfig configures a breed. A breed may declare a parent breed, which
+// it falls back to for layers it does not have (i.e. "maine_coon" ->
+// "longhair" -> "default"). Breeds with no parent fall back to "default".
+// Breeds that are only declared here (with no layers of their own) are added
+// to the breeds, so that breed variants only need the layers that differ.
+// The metadata is the breed's human-facing information (see Metadata).
 type BreedConfig struct {
-	BreedName  string
-	LayerTypes map[string]LayerShift
+	BreedName   string             `json:"breed"`
+	LayerShifts []LayerShift       `json:"layer_shifts"`
+	Parent      string             `json:"parent"`
+	Metadata    container.Metadata `json:"metadata"`
+}
+
+func (bc *BreedConfig) getLayerShift(ltName string) (*layer.Transformation, bool) {
+	for i, v := range bc.LayerShifts {
+		if v.LayerType == ltName {
+			return &bc.LayerShifts[i].Transformation, true
+		}
+	}
+	return nil, false
+}
+
+// breedChain returns the breeds to look up layers from, in order, starting
+// with the breed itself and ending with the default breed.
+func (lc *Layers) breedChain(bName string) []string {
+	chain := []string{bName}
+	// Parents are checked for cycles when compiled, but stay bounded anyway.
+	for bName != BreedDefault && len(chain) <= len(lc.BreedConfigs)+1 {
+		bName = lc.getBreedParent(bName)
+		chain = append(chain, bName)
+	}
+	return chain
+}
+
+func (lc *Layers) getBreedParent(bName string) string {
+	if bc, ok := lc.getBreedConfig(bName); ok && bc.Parent != "" {
+		return bc.Parent
+	}
+	return BreedDefault
+}
+
+/*
+	<<< HELPERS >>>
+*/
+
+func initBreedConfigs(lc *Layers, rootDir string) error {
+	data, e := ioutil.ReadFile(path.Join(rootDir, BreedsFileName))
+	switch {
+	case os.IsNotExist(e):
+		return nil
+	case e != nil:
+		return e
+	}
+	var configs []BreedConfig
+	if e := json.Unmarshal(data, &configs); e != nil {
+		return e
+	}
+	return setBreedConfigs(lc, configs)
+}
+
+// setBreedConfigs sets and checks the breed configs.
+func setBreedConfigs(lc *Layers, configs []BreedConfig) error {
+	for _, bc := range configs {
+		if _, has := lc.breedsByName[bc.BreedName]; !has {
+			log.WithField("breed", bc.BreedName).
+				Info("breed with no layers added from breed config")
+			lc.addBreed(bc.BreedName)
+		}
+		for _, shift := range bc.LayerShifts {
+			if _, has := lc.layerTypesByName[shift.LayerType]; !has {
+				return fmt.Errorf("breed '%s': layer type '%s' does not exist",
+					bc.BreedName, shift.LayerType)
+			}
+		}
+	}
+	lc.BreedConfigs = configs
+	return checkBreedParents(lc)
+}
+
+func checkBreedParents(lc *Layers) error {
+	for _, bc := range lc.BreedConfigs {
+		if bc.Parent == "" {
+			continue
+		}
+		if bc.BreedName == BreedDefault {
+			return fmt.Errorf("breed '%s' cannot have a parent", BreedDefault)
+		}
+		if _, has := lc.breedsByName[bc.Parent]; !has {
+			return fmt.Errorf("breed '%s': parent breed '%s' does not exist",
+				bc.BreedName, bc.Parent)
+		}
+		var (
+			seen = map[string]bool{bc.BreedName: true}
+			b    = bc.Parent
+		)
+		for b != BreedDefault {
+			if seen[b] {
+				return fmt.Errorf("breed '%s': parent breeds form a cycle", bc.BreedName)
+			}
+			seen[b] = true
+			b = lc.getBreedParent(b)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,121 @@
+package v0
+
+import (
+	"github.com/kittycash/kittiverse/src/kitty/generator/container"
+	"reflect"
+	"testing"
+)
+
+const testBreedsJSON = `[
+	{"breed": "longhair"},
+	{"breed": "maine_coon", "parent": "longhair",
+		"layer_shifts": [{"layer_type": "eyes", "transformation": {"shift_x": 10, "shift_y": -5}}]},
+	{"breed": "sphynx",
+		"layer_shifts": [{"layer_type": "ears", "transformation": {"shift_y": 3}}]}
+]`
+
+func TestLayers_BreedChain(t *testing.T) {
+	lc, _, _ := compileTestLayers(t, testFiles(map[string]string{
+		BreedsFileName: testBreedsJSON,
+	}))
+	cases := []struct {
+		breed string
+		exp   []string
+	}{
+		{"default", []string{"default"}},
+		{"longhair", []string{"longhair", "default"}},
+		{"maine_coon", []string{"maine_coon", "longhair", "default"}},
+		{"unknown", []string{"unknown", "default"}},
+	}
+	for _, c := range cases {
+		if got := lc.breedChain(c.breed); !reflect.DeepEqual(got, c.exp) {
+			t.Errorf("%s: expected chain %v, got %v", c.breed, c.exp, got)
+		}
+	}
+	// Breeds declared only by configs are added.
+	for _, b := range []string{"longhair", "maine_coon", "sphynx"} {
+		if _, ok := lc.breedsByName[b]; !ok {
+			t.Errorf("expected breed '%s' to be added", b)
+		}
+	}
+}
+
+func TestSetBreedConfigs(t *testing.T) {
+	cases := []struct {
+		name    string
+		configs []BreedConfig
+		expFail bool
+	}{
+		{"no parents", []BreedConfig{{BreedName: "a"}, {BreedName: "b"}}, false},
+		{"chain", []BreedConfig{{BreedName: "a", Parent: "b"}, {BreedName: "b", Parent: "c"}, {BreedName: "c"}}, false},
+		{"parent is default", []BreedConfig{{BreedName: "a", Parent: BreedDefault}}, false},
+		{"missing parent", []BreedConfig{{BreedName: "a", Parent: "b"}}, true},
+		{"default has parent", []BreedConfig{{BreedName: BreedDefault, Parent: "a"}, {BreedName: "a"}}, true},
+		{"self cycle", []BreedConfig{{BreedName: "a", Parent: "a"}}, true},
+		{"cycle", []BreedConfig{{BreedName: "a", Parent: "b"}, {BreedName: "b", Parent: "a"}}, true},
+		{"longer cycle", []BreedConfig{
+			{BreedName: "a", Parent: "b"}, {BreedName: "b", Parent: "c"}, {BreedName: "c", Parent: "b"}}, true},
+		{"shift of missing layer type", []BreedConfig{
+			{BreedName: "a", LayerShifts: []LayerShift{{LayerType: "hat"}}}}, true},
+		{"shift", []BreedConfig{
+			{BreedName: "a", LayerShifts: []LayerShift{{LayerType: "eyes"}}}}, false},
+	}
+	for _, c := range cases {
+		lc, _, _ := compileTestLayers(t, testFiles(nil))
+		e := setBreedConfigs(lc, c.configs)
+		if c.expFail && e == nil {
+			t.Errorf("%s: expected error", c.name)
+		}
+		if !c.expFail && e != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, e)
+		}
+	}
+}
+
+func TestLayers_FindLayer(t *testing.T) {
+	lc, _, _ := compileTestLayers(t, testFiles(map[string]string{
+		BreedsFileName:                  testBreedsJSON,
+		"eyes/longhair/a_outline.png":   "",
+		"ears/maine_coon/a_outline.png": "",
+	}))
+	cases := []struct {
+		layerType string
+		breed     string
+		expBreed  string
+		expStatus container.LayerStatus
+		expShiftX int16
+		expShiftY int16
+	}{
+		{"eyes", "default", "default", container.LayerOwn, 0, 0},
+		{"eyes", "longhair", "longhair", container.LayerOwn, 0, 0},
+		// Shifted as configured by maine_coon, which falls back to longhair.
+		{"eyes", "maine_coon", "longhair", container.LayerFallback, 10, -5},
+		{"eyes", "sphynx", "default", container.LayerFallback, 0, 0},
+		{"ears", "maine_coon", "maine_coon", container.LayerOwn, 0, 0},
+		{"ears", "longhair", "default", container.LayerFallback, 0, 0},
+		{"ears", "sphynx", "default", container.LayerFallback, 0, 3},
+	}
+	for _, c := range cases {
+		lt, _ := lc.getLayerType(c.layerType)
+		sel := lc.findLayer(lt, c.breed, "a")
+		if sel == nil {
+			t.Errorf("%s of %s: expected layer", c.layerType, c.breed)
+			continue
+		}
+		if sel.layer.OfBreed != c.expBreed || sel.status != c.expStatus {
+			t.Errorf("%s of %s: expected layer of %s (%s), got %s (%s)",
+				c.layerType, c.breed, c.expBreed, c.expStatus, sel.layer.OfBreed, sel.status)
+		}
+		var x, y int16
+		if sel.shift != nil {
+			x, y = sel.shift.ShiftX, sel.shift.ShiftY
+		}
+		if x != c.expShiftX || y != c.expShiftY {
+			t.Errorf("%s of %s: expected shift (%d, %d), got (%d, %d)",
+				c.layerType, c.breed, c.expShiftX, c.expShiftY, x, y)
+		}
+	}
+	if sel := lc.findLayer(&lc.LayerTypes[0], "default", "missing"); sel != nil {
+		t.Error("expected no layer of missing attribute")
+	}
+}
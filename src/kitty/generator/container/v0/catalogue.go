@@ -0,0 +1,216 @@
+package v0
+
+import (
+	"errors"
+	"fmt"
+	"github.com/kittycash/kittiverse/src/kitty/generator/container"
+	"github.com/kittycash/kittiverse/src/kitty/generator/container/common"
+	"github.com/kittycash/kittiverse/src/kitty/genetics"
+	"github.com/kittycash/kittiverse/src/kitty/graphics"
+	"strconv"
+	"strings"
+)
+
+const (
+	// BreedDefault is the breed of layers that are used when a breed has no
+	// layer of its own.
+	BreedDefault = "default"
+)
+
+var (
+	ErrNoGene = errors.New("layer type is not selected by any gene")
+)
+
+// GetLayerTypes returns the names of all layer types.
+func (lc *Layers) GetLayerTypes() []string {
+	out := make([]string, len(lc.LayerTypes))
+	for i, lt := range lc.LayerTypes {
+		out[i] = lt.OfType
+	}
+	return out
+}
+
+// GetBreeds returns the names of all breeds.
+func (lc *Layers) GetBreeds() []string {
+	return append([]string(nil), lc.Breeds...)
+}
+
+// GetAttributes returns the attributes of the layer type.
+func (lc *Layers) GetAttributes(ltName string) ([]string, bool) {
+	lt, ok := lc.getLayerType(ltName)
+	if !ok {
+		return nil, false
+	}
+	return append([]string(nil), lt.Attributes...), true
+}
+
+// GetLayerStatus determines which layer would render the attribute of the
+// layer type for the breed.
+func (lc *Layers) GetLayerStatus(ltName, bName, attribute string) container.LayerStatus {
+	lt, ok := lc.getLayerType(ltName)
+	if !ok {
+		return container.LayerMissing
+	}
+	sel := lc.findLayer(lt, bName, attribute)
+	if sel == nil {
+		return container.LayerMissing
+	}
+	return sel.status
+}
+
+// AttributeDNA modifies the base DNA to be of the given breed, and to render
+// the given attribute of the layer type. All alleles of the modified genes are
+// set, so the result does not depend on dominance.
+func (lc *Layers) AttributeDNA(base genetics.DNA, ltName, bName, attribute string) (genetics.DNA, error) {
+	lt, ok := lc.getLayerType(ltName)
+	if !ok {
+		return base, common.ErrDoesNotExist
+	}
+	bAllele, e := lc.GetAllele(genetics.DNABreedPos.String(), bName)
+	if e != nil {
+		return base, e
+	}
+	pos, ok := lc.getLayerTypeGene(ltName)
+	if !ok {
+		return base, ErrNoGene
+	}
+	if !base.HasGene(pos) {
+		return base, errors.New("base DNA of version " +
+			strconv.Itoa(int(base.Version())) + " has no gene " + pos.String())
+	}
+	aAllele, ok := lc.getAttributeAllele(lt, attribute)
+	if !ok {
+		return base, common.ErrDoesNotExist
+	}
+
+	dna := base
+	dna.SetGenotype(genetics.DNABreedPos, bAllele, bAllele, bAllele)
+	dna.SetGenotype(pos, aAllele, aAllele, aAllele)
+	return dna, nil
+}
+
+// GetAllele obtains the allele of the gene that expresses the named attribute
+// (or breed, for the breed gene). Accessories are named by attribute, or by
+// "<slot>/<attribute>" if the attribute exists in multiple slots, and "none"
+// represents no accessory.
+func (lc *Layers) GetAllele(gene, name string) (genetics.Allele, error) {
+	var none genetics.Allele
+	pos, ok := genetics.NewDNAPosFromString(gene)
+	if !ok {
+		return none, fmt.Errorf("gene '%s' does not exist", gene)
+	}
+	switch pos {
+	case genetics.DNABreedPos:
+		i, ok := lc.breedsByName[name]
+		if !ok {
+			return none, fmt.Errorf("breed '%s' does not exist", name)
+		}
+		return genetics.NewAlleleFromUint16(uint16(i)), nil
+
+	case genetics.DNAAccessoryPos:
+		if name == AccessoryNone {
+			return none, nil
+		}
+		var (
+			slot, attribute = "", name
+			found           []genetics.Allele
+		)
+		if i := strings.Index(name, "/"); i >= 0 {
+			slot, attribute = name[:i], name[i+1:]
+		}
+		for _, acc := range lc.getAccessories() {
+			if acc.Attribute == attribute && (slot == "" || acc.Slot == slot) {
+				found = append(found, acc.Allele)
+			}
+		}
+		switch len(found) {
+		case 0:
+			return none, fmt.Errorf("accessory '%s' does not exist", name)
+		case 1:
+			return found[0], nil
+		default:
+			return none, fmt.Errorf("accessory '%s' exists in multiple slots, use '<slot>/%s'",
+				name, attribute)
+		}
+	}
+
+	lt, ok := lc.getGeneLayerType(pos)
+	if !ok {
+		return none, fmt.Errorf("gene '%s': %v", gene, ErrNoGene)
+	}
+	a, ok := lc.getAttributeAllele(lt, name)
+	if !ok {
+		return none, fmt.Errorf("attribute '%s' of layer type '%s' does not exist",
+			name, lt.OfType)
+	}
+	return a, nil
+}
+
+/*
+	<<< HELPERS >>>
+*/
+
+// findLayer finds the layer of the attribute by walking the fallback chain of
+// the breed (see breedChain). When a layer of another breed is used, it is
+// shifted as configured by the nearest breed in the chain before it. It
+// returns nil if no breed in the chain has the layer.
+func (lc *Layers) findLayer(lt *LayersOfType, bName, attribute string) *layerSelection {
+	var shift *layer.Transformation
+	for i, b := range lc.breedChain(bName) {
+		if l, ok := lt.get(newAttributeKey(attribute, b)); ok {
+			if i == 0 {
+				return &layerSelection{layer: l, status: container.LayerOwn}
+			}
+			return &layerSelection{layer: l, shift: shift, status: container.LayerFallback}
+		}
+		if shift == nil {
+			shift, _ = lc.getLayerShift(b, lt.OfType)
+		}
+	}
+	return nil
+}
+
+// getGeneLayerType obtains the layer type of which the gene selects the
+// attribute (the first, as defined by the render steps).
+func (lc *Layers) getGeneLayerType(pos genetics.DNAPos) (*LayersOfType, bool) {
+	for _, step := range lc.RenderSteps {
+		if step.gene() == pos.String() && !isAccessorySlot(step.LayerType) {
+			if lt, ok := lc.getLayerType(step.LayerType); ok {
+				return lt, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// getAttributeAllele obtains the allele that selects the attribute of the
+// layer type.
+func (lc *Layers) getAttributeAllele(lt *LayersOfType, attribute string) (genetics.Allele, bool) {
+	if isAccessorySlot(lt.OfType) {
+		for _, acc := range lc.getAccessories() {
+			if acc.Slot == lt.OfType && acc.Attribute == attribute {
+				return acc.Allele, true
+			}
+		}
+		return genetics.Allele{}, false
+	}
+	i, ok := lt.attributesByName[attribute]
+	if !ok {
+		return genetics.Allele{}, false
+	}
+	return genetics.NewAlleleFromUint16(uint16(i)), true
+}
+
+// getLayerTypeGene obtains the gene that selects the attribute of the layer
+// type, as defined by the render steps.
+func (lc *Layers) getLayerTypeGene(ltName string) (genetics.DNAPos, bool) {
+	for _, step := range lc.RenderSteps {
+		if step.LayerType != ltName {
+			continue
+		}
+		if pos, ok := genetics.NewDNAPosFromString(step.gene()); ok {
+			return pos, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,109 @@
+package v0
+
+import (
+	"github.com/kittycash/kittiverse/src/kitty/generator/container"
+	"github.com/kittycash/kittiverse/src/kitty/genetics"
+	"testing"
+)
+
+func TestLayers_GetAllele(t *testing.T) {
+	lc, _, _ := compileTestLayers(t, testFiles(map[string]string{
+		"eyes/default/b_outline.png":     "",
+		"eyes/persian/a_outline.png":     "",
+		"whiskers/default/a_outline.png": "", // not rendered.
+	}))
+	cases := []struct {
+		gene    string
+		name    string
+		exp     uint16
+		expFail bool
+	}{
+		{"breed", "default", 0, false},
+		{"breed", "persian", 1, false},
+		{"breed", "sphynx", 0, true},
+		{"eyes", "a", 0, false},
+		{"eyes", "b", 1, false},
+		{"eyes", "c", 0, true},
+		{"bodyPattern", "a", 0, false},
+		{"reservedA", "a", 0, true}, // selects no layer type.
+		{"whiskers", "a", 0, true},  // not a gene.
+	}
+	for _, c := range cases {
+		a, e := lc.GetAllele(c.gene, c.name)
+		if c.expFail {
+			if e == nil {
+				t.Errorf("%s '%s': expected error", c.gene, c.name)
+			}
+			continue
+		}
+		if e != nil {
+			t.Errorf("%s '%s': unexpected error: %v", c.gene, c.name, e)
+			continue
+		}
+		if a.Uint16() != c.exp {
+			t.Errorf("%s '%s': expected allele %04x, got %04x", c.gene, c.name, c.exp, a.Uint16())
+		}
+	}
+}
+
+func TestLayers_AttributeDNA(t *testing.T) {
+	lc, _, _ := compileTestLayers(t, testFiles(map[string]string{
+		"eyes/default/b_outline.png":     "",
+		"eyes/persian/a_outline.png":     "",
+		"whiskers/default/a_outline.png": "", // not rendered.
+		"accessory_hat/default/a.png":    "",
+	}))
+	var base genetics.DNA
+	base.SetVersion(genetics.DNAVersion)
+	ears := genetics.NewAlleleFromUint16(7)
+	base.SetGenotype(genetics.DNAEarsAttrPos, ears, ears, ears)
+
+	cases := []struct {
+		layerType string
+		breed     string
+		attribute string
+		expGene   genetics.DNAPos
+		expAllele uint16
+		expFail   bool
+	}{
+		{"eyes", "persian", "b", genetics.DNAEyesAttrPos, 1, false},
+		{"eyes", "default", "a", genetics.DNAEyesAttrPos, 0, false},
+		{"accessory_hat", "default", "a", genetics.DNAAccessoryPos, 0x0100, false},
+		{"hat", "default", "a", 0, 0, true},
+		{"eyes", "sphynx", "a", 0, 0, true},
+		{"eyes", "default", "c", 0, 0, true},
+		{"whiskers", "default", "a", 0, 0, true},
+	}
+	for _, c := range cases {
+		name := c.layerType + " " + c.attribute + " of " + c.breed
+		dna, e := lc.AttributeDNA(base, c.layerType, c.breed, c.attribute)
+		if c.expFail {
+			if e == nil {
+				t.Errorf("%s: expected error", name)
+			}
+			continue
+		}
+		if e != nil {
+			t.Errorf("%s: unexpected error: %v", name, e)
+			continue
+		}
+		breed, _ := lc.GetAllele("breed", c.breed)
+		for pos, exp := range map[genetics.DNAPos]uint16{
+			genetics.DNABreedPos:    breed.Uint16(),
+			c.expGene:               c.expAllele,
+			genetics.DNAEarsAttrPos: ears.Uint16(), // unchanged.
+		} {
+			g := dna.GetGenotype(pos)
+			for _, slot := range []genetics.AlleleSlot{
+				genetics.AlleleRecessive1, genetics.AlleleRecessive2, genetics.AlleleDominant} {
+				if a := g.Get(slot); a.Uint16() != exp {
+					t.Errorf("%s: expected allele %s of %s to be %04x, got %04x",
+						name, slot, pos, exp, a.Uint16())
+				}
+			}
+		}
+		if status := lc.GetLayerStatus(c.layerType, c.breed, c.attribute); status == container.LayerMissing {
+			t.Errorf("%s: expected layer", name)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package v0
+
+import (
+	"encoding/json"
+	"github.com/kittycash/kittiverse/src/kitty/genetics"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+)
+
+const (
+	// DominanceFileName is the name of the optional file (within the root
+	// directory of loose files) that contains the dominance of genes (as a
+	// genetics.DominanceModel). Genes not listed are of strict dominance.
+	DominanceFileName = "dominance.json"
+)
+
+// NamedDominance is the dominance of a named gene.
+type NamedDominance struct {
+	Gene      string
+	Dominance genetics.Dominance
+}
+
+// GetDominance returns the dominance of genes.
+func (lc *Layers) GetDominance() genetics.DominanceModel {
+	m := make(genetics.DominanceModel, len(lc.Dominance))
+	for _, nd := range lc.Dominance {
+		m[nd.Gene] = nd.Dominance
+	}
+	return m
+}
+
+/*
+	<<< HELPERS >>>
+*/
+
+func (lc *Layers) getDominance(pos genetics.DNAPos) genetics.Dominance {
+	for _, nd := range lc.Dominance {
+		if nd.Gene == pos.String() {
+			return nd.Dominance
+		}
+	}
+	return genetics.Dominance{}
+}
+
+func initDominance(lc *Layers, rootDir string) error {
+	data, e := ioutil.ReadFile(path.Join(rootDir, DominanceFileName))
+	switch {
+	case os.IsNotExist(e):
+		return nil
+	case e != nil:
+		return e
+	}
+	var m genetics.DominanceModel
+	if e := json.Unmarshal(data, &m); e != nil {
+		return e
+	}
+	return setDominance(lc, m)
+}
+
+// setDominance sets and checks the dominance of genes.
+func setDominance(lc *Layers, m genetics.DominanceModel) error {
+	if e := m.Check(); e != nil {
+		return e
+	}
+	genes := make([]string, 0, len(m))
+	for gene := range m {
+		genes = append(genes, gene)
+	}
+	sort.Strings(genes)
+	lc.Dominance = nil
+	for _, gene := range genes {
+		lc.Dominance = append(lc.Dominance, NamedDominance{Gene: gene, Dominance: m[gene]})
+	}
+	return nil
+}
@@ -14,7 +14,9 @@ func init() {
 }
 
 const (
-	version uint16 = 0
+	// version is the version of the encoded containers. Version 1 changed the
+	// layout of layers (see layersV0), but not that of images.
+	version uint16 = 1
 )
 
 type Images struct {
@@ -42,7 +44,7 @@ func (ic *Images) Import(raw []byte) error {
 	if e := encoder.DeserializeRaw(raw[:common.VersionLen], &ver); e != nil {
 		return e
 	}
-	if ver != version {
+	if ver > version {
 		return common.ErrInvalidVersion
 	}
 	// Load data.
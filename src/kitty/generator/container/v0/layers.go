@@ -2,9 +2,11 @@ package v0
 
 import (
 	"errors"
+	"fmt"
 	"github.com/kittycash/kittiverse/src/kitty/generator/container"
 	"github.com/kittycash/kittiverse/src/kitty/generator/container/common"
 	"github.com/kittycash/kittiverse/src/kitty/genetics"
+	"github.com/kittycash/kittiverse/src/kitty/graphics"
 	"github.com/skycoin/skycoin/src/cipher"
 	"github.com/skycoin/skycoin/src/cipher/encoder"
 	"image"
@@ -12,16 +14,18 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 )
 
-const (
-	PrefixAccessory = "accessory"
-)
-
 type Layers struct {
 	LayerTypes       []LayersOfType
 	Breeds           []string
+	RenderSteps      []RenderStep
+	BreedConfigs     []BreedConfig
+	Backgrounds      []Background
+	Dominance        []NamedDominance
+	AccessorySlots   []string // layer types of accessory slots, in order of alleles.
 	layerTypesByName map[string]int `enc:"-"`
 	breedsByName     map[string]int `enc:"-"`
 }
@@ -47,14 +51,24 @@ func (lc *Layers) Import(raw []byte) error {
 	if e := encoder.DeserializeRaw(raw[:common.VersionLen], &ver); e != nil {
 		return e
 	}
-	if ver != version {
+	// Load data (of the layout of the version).
+	switch ver {
+	case version:
+		if e := encoder.DeserializeRaw(raw[common.VersionLen:], lc); e != nil {
+			return e
+		}
+		lc.prepareMaps()
+	case 0:
+		if e := importLayersV0(lc, raw[common.VersionLen:]); e != nil {
+			return e
+		}
+	default:
 		return common.ErrInvalidVersion
 	}
-	// Load data.
-	if e := encoder.DeserializeRaw(raw[common.VersionLen:], lc); e != nil {
-		return e
-	}
-	// Prepare maps.
+	return nil
+}
+
+func (lc *Layers) prepareMaps() {
 	lc.layerTypesByName = make(map[string]int)
 	for i, v := range lc.LayerTypes {
 		lc.layerTypesByName[v.OfType] = i
@@ -64,7 +78,6 @@ func (lc *Layers) Import(raw []byte) error {
 	for i, v := range lc.Breeds {
 		lc.breedsByName[v] = i
 	}
-	return nil
 }
 
 func (lc *Layers) Export() []byte {
@@ -72,126 +85,173 @@ func (lc *Layers) Export() []byte {
 }
 
 func (lc *Layers) Compile(rootDir string, images container.Images) error {
+	return lc.CompileWithReport(rootDir, images, nil)
+}
+
+// CompileWithReport compiles the layers, collecting issues with the files
+// into the report (if not nil). Only failures that prevent compiling are
+// returned as errors, unless the report is strict and errors are found.
+func (lc *Layers) CompileWithReport(rootDir string, images container.Images, report *container.Report) error {
 	// Get layer types.
 	if e := initLayerTypes(lc, rootDir); e != nil {
 		log.WithError(e).Error("failed to initiate later types")
 		return e
 	}
 	// Get layers.
-	if e := initLayers(lc, rootDir, images); e != nil {
+	if e := initLayers(lc, rootDir, images, report); e != nil {
 		log.WithError(e).Error("failed to initiate layers")
 		return e
 	}
-	return nil
+	// Get accessory slots.
+	if e := initAccessorySlots(lc, rootDir); e != nil {
+		log.WithError(e).Error("failed to initiate accessory slots")
+		return e
+	}
+	// Get breed configs.
+	if e := initBreedConfigs(lc, rootDir); e != nil {
+		log.WithError(e).Error("failed to initiate breed configs")
+		return e
+	}
+	// Check alleles of all genes fit.
+	if e := lc.GetAlleleRanges().Check(); e != nil {
+		log.WithError(e).Error("failed to check allele ranges")
+		return e
+	}
+	lintLayers(lc, report)
+	// Get metadata.
+	if e := initMetadata(lc, rootDir, report); e != nil {
+		log.WithError(e).Error("failed to initiate metadata")
+		return e
+	}
+	// Get dominance of genes.
+	if e := initDominance(lc, rootDir); e != nil {
+		log.WithError(e).Error("failed to initiate dominance")
+		return e
+	}
+	// Get render steps.
+	if e := initRenderSteps(lc, rootDir); e != nil {
+		log.WithError(e).Error("failed to initiate render steps")
+		return e
+	}
+	// Get backgrounds.
+	if e := initBackgrounds(lc, rootDir, images, report); e != nil {
+		log.WithError(e).Error("failed to initiate backgrounds")
+		return e
+	}
+	return report.StrictErr()
 }
 
 func (lc *Layers) GetAlleleRanges() *genetics.AlleleRanges {
-	getRange := func(pos genetics.DNAPos) genetics.AlleleRange {
-		return genetics.AlleleRange{
-			Min: genetics.Allele{}.String(),
-			Max: genetics.NewAlleleFromUint16(uint16(
-				len(lc.LayerTypes[lc.layerTypesByName[pos.String()]].Attributes) - 1,
-			)).String(),
+	r := genetics.NewAlleleRanges(genetics.LatestSchema())
+	for _, pos := range genetics.GenePositions() {
+		var count int
+		switch pos {
+		case genetics.DNABreedPos:
+			count = len(lc.Breeds)
+		case genetics.DNAAccessoryPos:
+			// Allele 0 represents no accessory, and alleles in between the
+			// accessories of slots represent none either, so only the alleles
+			// of no accessory and of each accessory are valid.
+			ar := genetics.AlleleRange{Min: genetics.Allele{}.String()}
+			ar.Values = []string{ar.Min}
+			for _, acc := range lc.getAccessories() {
+				ar.Values = append(ar.Values, acc.Allele.String())
+			}
+			ar.Max = ar.Values[len(ar.Values)-1]
+			r.Set(pos, ar)
+			continue
+		default:
+			if lt, ok := lc.getLayerType(pos.String()); ok {
+				count = len(lt.Attributes)
+			}
 		}
-	}
-	return &genetics.AlleleRanges{
-		Breed: genetics.AlleleRange{
+		if count == 0 {
+			count = 1
+		}
+		r.Set(pos, genetics.AlleleRange{
 			Min: genetics.Allele{}.String(),
-			Max: genetics.NewAlleleFromUint16(uint16(
-				len(lc.Breeds) - 1,
-			)).String(),
-		},
-		BodyAttribute: getRange(genetics.DNABodyAttrPos),
-		BodyColorA:    getRange(genetics.DNABodyColorAPos),
-		BodyColorB:    getRange(genetics.DNABodyColorBPos),
-		BodyPattern:   getRange(genetics.DNABodyPatternPos),
-		EarsAttribute: getRange(genetics.DNAEarsAttrPos),
-		EyesAttribute: getRange(genetics.DNAEyesAttrPos),
-		EyesColor:     getRange(genetics.DNAEyesColorPos),
-		NoseAttribute: getRange(genetics.DNANoseAttrPos),
-		TailAttribute: getRange(genetics.DNATailAttrPos),
+			Max: genetics.NewAlleleFromUint16(uint16(count - 1)).String(),
+		})
 	}
+	return r
 }
 
 func (lc *Layers) GenerateKitty(ic container.Images, dna genetics.DNA) (image.Image, error) {
-	out := image.NewRGBA(image.Rect(0, 0, common.XpxLen, common.YpxLen))
-
-	// Get breed.
-	breed := lc.getBreed(dna.GetPhenotype(genetics.DNABreedPos))
+	frames, e := lc.generateKitty(ic, dna, 1)
+	if e != nil {
+		return nil, e
+	}
+	return frames[0], nil
+}
 
-	// Make image input common.
-	iic := &imgInputCommon{lc: lc, ic: ic, breed: breed, dna: dna}
+func (lc *Layers) GenerateKittyFrames(ic container.Images, dna genetics.DNA) ([]image.Image, error) {
+	return lc.generateKitty(ic, dna, 0)
+}
 
-	// Generate fur.
-	fur := common.EmptyImage()
-	{
-		bg, e := generateImage(iic, genetics.DNABodyColorAPos, nil)
-		if e != nil {
-			return nil, e
-		}
-		fg, e := generateImage(iic, genetics.DNABodyColorBPos, nil)
-		if e != nil {
-			return nil, e
-		}
-		pt, e := generateImage(iic, genetics.DNABodyPatternPos, fg)
-		if e != nil {
-			return nil, e
-		}
-		common.DrawOutline(fur, bg)
-		common.DrawOutline(fur, pt)
+// generateKitty generates the frames of a kitty (limited to maxFrames if > 0).
+// The number of frames is that of the selected layer with the most frames.
+func (lc *Layers) generateKitty(ic container.Images, dna genetics.DNA, maxFrames int) ([]image.Image, error) {
+	// Genes are selected as of the schema of the DNA's version.
+	if e := genetics.CheckVersion(dna.Version()); e != nil {
+		return nil, e
 	}
 
-	// Generate ears.
-	{
-		ears, e := generateImage(iic, genetics.DNAEarsAttrPos, fur)
-		if e != nil {
-			return nil, e
-		}
-		common.DrawOutline(out, ears)
+	// Get breed.
+	breed, e := lc.expressBreed(dna)
+	if e != nil {
+		return nil, e
 	}
 
-	// Generate tail.
-	{
-		tail, e := generateImage(iic, genetics.DNATailAttrPos, fur)
-		if e != nil {
-			return nil, e
-		}
-		common.DrawOutline(out, tail)
-	}
+	// Make image input common.
+	iic := &imgInputCommon{lc: lc, ic: ic, breed: breed, dna: dna}
 
-	// Generate body.
-	{
-		body, e := generateImage(iic, genetics.DNABodyAttrPos, fur)
-		if e != nil {
+	// Select layers of render steps.
+	var (
+		selections = make([]*layerSelection, len(lc.RenderSteps))
+		frameCount = 1
+	)
+	for i := range lc.RenderSteps {
+		if selections[i], e = selectLayer(iic, &lc.RenderSteps[i]); e != nil {
 			return nil, e
 		}
-		common.DrawOutline(out, body)
-	}
-
-	// Generate nose.
-	{
-		// TODO: Change to noseColor.
-		nose, e := generateImage(iic, genetics.DNANoseAttrPos, fur)
-		if e != nil {
-			return nil, e
+		if selections[i] != nil && selections[i].layer.FrameCount() > frameCount {
+			frameCount = selections[i].layer.FrameCount()
 		}
-		common.DrawOutline(out, nose)
+	}
+	if maxFrames > 0 && frameCount > maxFrames {
+		frameCount = maxFrames
 	}
 
-	// Generate eyes.
-	{
-		bg, e := generateImage(iic, genetics.DNAEyesColorPos, nil)
-		if e != nil {
-			return nil, e
-		}
-		fg, e := generateImage(iic, genetics.DNAEyesAttrPos, bg)
-		if e != nil {
-			return nil, e
+	// Follow render steps for each frame.
+	frames := make([]image.Image, frameCount)
+	for f := range frames {
+		canvases := map[string]draw.Image{CanvasKitty: common.EmptyImage()}
+		for i, sel := range selections {
+			if sel == nil {
+				continue
+			}
+			var (
+				step   = &lc.RenderSteps[i]
+				canvas = canvases[step.Canvas]
+				fill   = canvases[step.Fill]
+			)
+			if fill == nil {
+				fill = canvases[step.FillFallback]
+			}
+			src, e := sel.layer.generateImage(ic, fill, f, sel.shift, step.parts()...)
+			if e != nil {
+				return nil, e
+			}
+			if canvas == nil {
+				canvas = common.EmptyImage()
+				canvases[step.Canvas] = canvas
+			}
+			common.DrawOutline(canvas, src)
 		}
-		common.DrawOutline(out, fg)
+		frames[f] = canvases[CanvasKitty]
 	}
 
-	return out, nil
+	return frames, nil
 }
 
 /*
@@ -200,7 +260,7 @@ func (lc *Layers) GenerateKitty(ic container.Images, dna genetics.DNA) (image.Im
 
 type layerTypeAction func(lt *LayersOfType, ltDir string, breedDirs []os.FileInfo) error
 
-func (lc *Layers) rangeLayerTypes(rootDir string, action layerTypeAction) {
+func (lc *Layers) rangeLayerTypes(rootDir string, report *container.Report, action layerTypeAction) {
 	for i := 0; i < len(lc.LayerTypes); i++ {
 		var (
 			lt    = &lc.LayerTypes[i]
@@ -212,9 +272,13 @@ func (lc *Layers) rangeLayerTypes(rootDir string, action layerTypeAction) {
 				WithField("layer_type", lt.OfType).
 				WithField("dir", ltDir).
 				WithError(e).Error("failed to read directory")
+			report.Errorf(container.Issue{Path: ltDir, LayerType: lt.OfType},
+				"failed to read directory: %v", e)
 			continue
 		}
-		action(lt, ltDir, breedDirs)
+		if e := action(lt, ltDir, breedDirs); e != nil {
+			report.Errorf(container.Issue{Path: ltDir, LayerType: lt.OfType}, "%v", e)
+		}
 	}
 }
 
@@ -240,12 +304,45 @@ func (lc *Layers) addBreed(bName string) error {
 	return nil
 }
 
-func (lc *Layers) getLayerType(pos genetics.DNAPos) *LayersOfType {
-	return &lc.LayerTypes[lc.layerTypesByName[pos.String()]]
+func (lc *Layers) getLayerType(ltName string) (*LayersOfType, bool) {
+	i, has := lc.layerTypesByName[ltName]
+	if !has {
+		return nil, false
+	}
+	return &lc.LayerTypes[i], true
+}
+
+// expressBreed obtains the breed of the DNA, as of the dominance of the breed
+// gene.
+func (lc *Layers) expressBreed(dna genetics.DNA) (string, error) {
+	pos := genetics.DNABreedPos
+	return lc.getBreed(dna.Express(pos, lc.getDominance(pos)).Allele)
+}
+
+func (lc *Layers) getBreed(a genetics.Allele) (string, error) {
+	i := int(a.Uint16())
+	if i >= len(lc.Breeds) {
+		log.WithField("index", i).
+			Error("breed index out of range")
+		return "", errors.New("breed index out of range")
+	}
+	return lc.Breeds[i], nil
 }
 
-func (lc *Layers) getBreed(a genetics.Allele) string {
-	return lc.Breeds[a.Uint16()]
+func (lc *Layers) getBreedConfig(bName string) (*BreedConfig, bool) {
+	for i, v := range lc.BreedConfigs {
+		if v.BreedName == bName {
+			return &lc.BreedConfigs[i], true
+		}
+	}
+	return nil, false
+}
+
+func (lc *Layers) getLayerShift(bName, ltName string) (*layer.Transformation, bool) {
+	if bc, ok := lc.getBreedConfig(bName); ok {
+		return bc.getLayerShift(ltName)
+	}
+	return nil, false
 }
 
 /*
@@ -262,9 +359,9 @@ func initLayerTypes(lc *Layers, rootDir string) error {
 			continue
 		}
 		dirName := dir.Name()
-		//if strings.HasPrefix(dirName, PrefixAccessory+"_") {
-		//	continue
-		//}
+		if dirName == BackgroundsDirName {
+			continue
+		}
 		if e := lc.addLayerType(dirName); e != nil {
 			return e
 		}
@@ -272,9 +369,9 @@ func initLayerTypes(lc *Layers, rootDir string) error {
 	return nil
 }
 
-func initLayers(lc *Layers, rootDir string, images container.Images) error {
+func initLayers(lc *Layers, rootDir string, images container.Images, report *container.Report) error {
 
-	lc.rangeLayerTypes(rootDir, func(lt *LayersOfType, ltDir string, bDirs []os.FileInfo) error {
+	lc.rangeLayerTypes(rootDir, report, func(lt *LayersOfType, ltDir string, bDirs []os.FileInfo) error {
 		log.WithField("dir", ltDir).
 			WithField("breed_count", len(bDirs)).
 			Printf("ranging later type '%s'", lt.OfType)
@@ -302,8 +399,15 @@ func initLayers(lc *Layers, rootDir string, images container.Images) error {
 
 					attributeName = splitName[0]
 					partIndex     = 0
+					frameIndex    = 0
 					isArea        = false
 					isOutline     = false
+					issue         = container.Issue{
+						Path:      fullPath,
+						LayerType: lt.OfType,
+						Breed:     breed,
+						Attribute: attributeName,
+					}
 				)
 				for i := 1; i < len(splitName); i++ {
 					v := splitName[i]
@@ -313,31 +417,33 @@ func initLayers(lc *Layers, rootDir string, images container.Images) error {
 					case v == "right":
 						partIndex = 1
 					case strings.HasPrefix(v, "part"):
-						partIndex = getPartIndex(v)
+						var ok bool
+						if partIndex, ok = getPartIndex(v); !ok {
+							report.Errorf(issue, "invalid part token '%s', expected 'partA' to 'partZ'", v)
+						}
+					case strings.HasPrefix(v, "frame"):
+						var ok bool
+						if frameIndex, ok = getFrameIndex(v); !ok {
+							report.Errorf(issue, "invalid frame token '%s', using frame 0", v)
+						}
 					case v == "area":
 						isArea = true
 					case v == "outline":
 						isOutline = true
+					default:
+						report.Warnf(issue, "unknown filename token '%s' is ignored", v)
 					}
 				}
 				// Checks.
+				if isArea && isOutline {
+					report.Errorf(issue, "filename has both 'area' and 'outline' tokens, treated as outline")
+				}
 				if isArea && isOutline || !isArea && !isOutline {
 					isArea, isOutline = false, true
 				}
-				// Extract image.
-				imgRaw, e := common.GetRawImageFromFile(fullPath)
-				if e != nil {
-					log.WithError(e).Error("failed to read image file")
-				}
-				imgHash := images.GetOrAdd(imgRaw)
-				// Append.
-				layer := lt.getOrAddLayer(Layer{OfAttribute: attributeName, OfBreed: breed})
-				layer.ensurePartsCount(partIndex + 1)
-				switch {
-				case isArea:
-					layer.Parts[partIndex][0] = imgHash
-				case isOutline:
-					layer.Parts[partIndex][1] = imgHash
+				// Extract image and append.
+				if !addLayerImage(lt, images, report, issue, frameIndex, partIndex, isArea) {
+					continue
 				}
 				// Ensure attribute.
 				if e := lt.addAttribute(attributeName); e != nil {
@@ -362,9 +468,47 @@ func initLayers(lc *Layers, rootDir string, images container.Images) error {
 	return nil
 }
 
-func getPartIndex(str string) int {
+// addLayerImage reads the image file (of the issue's path), and adds it as the
+// area or outline of the given frame and part of the layer of the issue's
+// attribute and breed. It returns false if the file cannot be read.
+func addLayerImage(lt *LayersOfType, images container.Images, report *container.Report,
+	issue container.Issue, frame, part int, isArea bool) bool {
+
+	imgRaw, e := common.GetRawImageFromFile(issue.Path)
+	if e != nil {
+		log.WithError(e).Error("failed to read image file")
+		report.Errorf(issue, "failed to read image file: %v", e)
+		return false
+	}
+	checkLayerImage(report, issue, imgRaw)
+	imgHash := images.GetOrAdd(imgRaw)
+
+	layer := lt.getOrAddLayer(Layer{OfAttribute: issue.Attribute, OfBreed: issue.Breed})
+	parts := layer.ensurePartsCount(frame, part+1)
+	if isArea {
+		parts[part][0] = imgHash
+	} else {
+		parts[part][1] = imgHash
+	}
+	return true
+}
+
+func getPartIndex(str string) (int, bool) {
 	p := strings.TrimPrefix(str, "part")
-	return int([]byte(p)[0] - 65)
+	if len(p) != 1 || p[0] < 'A' || p[0] > 'Z' {
+		log.WithField("token", str).Warn("invalid part index, using part 0")
+		return 0, false
+	}
+	return int(p[0] - 'A'), true
+}
+
+func getFrameIndex(str string) (int, bool) {
+	f, e := strconv.Atoi(strings.TrimPrefix(str, "frame"))
+	if e != nil || f < 0 {
+		log.WithField("token", str).Warn("invalid frame index, using frame 0")
+		return 0, false
+	}
+	return f, true
 }
 
 type imgInputCommon struct {
@@ -374,34 +518,88 @@ type imgInputCommon struct {
 	dna   genetics.DNA
 }
 
-func generateImage(c *imgInputCommon, dnaPos genetics.DNAPos, bg image.Image, ps ...int) (image.Image, error) {
-	var (
-		allele    = c.dna.GetPhenotype(dnaPos)
-		lt        = c.lc.getLayerType(dnaPos)
-		attribute = lt.Attributes[allele.Uint16()]
-	)
-
-	layer, ok := lt.get(newAttributeKey(attribute, c.breed))
+// getAttribute obtains the attribute of the layer type that is selected by the
+// gene of the render step. False is returned if no attribute is selected.
+func (c *imgInputCommon) getAttribute(lt *LayersOfType, step *RenderStep) (string, bool, error) {
+	pos, ok := genetics.NewDNAPosFromString(step.gene())
+	if !ok {
+		if len(lt.Attributes) == 0 {
+			return "", false, nil
+		}
+		return lt.Attributes[0], true, nil
+	}
+	a, ok := c.getAllele(pos, step)
 	if !ok {
-		layer, ok = lt.get(newAttributeKey(attribute, "default"))
-		if !ok {
-			log.WithField("layer_type", lt.OfType).
-				WithField("breed", c.breed).
-				WithField("attribute", attribute).
-				Error("failed to find layer")
-			return nil, errors.New("failed to find layer")
+		return "", false, nil
+	}
+	index := int(a.Uint16())
+
+	// Accessory slots are selected from all accessories.
+	if isAccessorySlot(lt.OfType) {
+		acc, ok, e := c.lc.getAccessory(a)
+		if e != nil || !ok {
+			return "", false, e
 		}
+		return acc.Attribute, acc.Slot == lt.OfType, nil
+	}
+
+	if index >= len(lt.Attributes) {
+		log.WithField("layer_type", lt.OfType).
+			WithField("gene", step.gene()).
+			WithField("index", index).
+			Error("attribute index out of range")
+		return "", false, errors.New("attribute index out of range")
 	}
-	return layer.generateImage(c.ic, bg, ps...)
+	return lt.Attributes[index], true, nil
 }
 
-func overlayImage(c *imgInputCommon, dst draw.Image, dnaPos genetics.DNAPos, bg image.Image, ps ...int) error {
-	src, e := generateImage(c, dnaPos, bg, ps...)
-	if e != nil {
-		return e
+// getAllele obtains the allele of the gene that selects the attribute of the
+// render step, as of the gene's dominance. False is returned if the DNA's
+// version does not express the gene, or if the step draws the secondary
+// allele and none is expressed.
+func (c *imgInputCommon) getAllele(pos genetics.DNAPos, step *RenderStep) (genetics.Allele, bool) {
+	if !c.dna.HasGene(pos) {
+		return genetics.Allele{}, false
 	}
-	common.DrawOutline(dst, src)
-	return nil
+	expr := c.dna.Express(pos, c.lc.getDominance(pos))
+	if !step.Secondary {
+		return expr.Allele, true
+	}
+	return expr.Secondary, expr.HasSecondary
+}
+
+// layerSelection is a layer selected for a render step.
+type layerSelection struct {
+	layer  *Layer
+	shift  *layer.Transformation // (optional) transformation of fallback layers.
+	status container.LayerStatus
+}
+
+// selectLayer selects the layer for the given render step.
+// Nil is returned if the render step is to be skipped.
+func selectLayer(c *imgInputCommon, step *RenderStep) (*layerSelection, error) {
+	lt, ok := c.lc.getLayerType(step.LayerType)
+	if !ok {
+		if step.Optional {
+			return nil, nil
+		}
+		return nil, errors.New("failed to find layer type")
+	}
+
+	attribute, ok, e := c.getAttribute(lt, step)
+	if e != nil || !ok {
+		return nil, e
+	}
+
+	sel := c.lc.findLayer(lt, c.breed, attribute)
+	if sel == nil {
+		log.WithField("layer_type", lt.OfType).
+			WithField("breed", c.breed).
+			WithField("attribute", attribute).
+			Error("failed to find layer")
+		return nil, errors.New("failed to find layer")
+	}
+	return sel, nil
 }
 
 /*
@@ -409,11 +607,12 @@ func overlayImage(c *imgInputCommon, dst draw.Image, dnaPos genetics.DNAPos, bg
 */
 
 type LayersOfType struct {
-	OfType           string
-	Layers           []Layer
-	Attributes       []string
-	layersByKey      map[attributeKey]int `enc:"-"` // aka, by attribute and breed
-	attributesByName map[string]int       `enc:"-"`
+	OfType            string
+	Layers            []Layer
+	Attributes        []string
+	AttributeMetadata []NamedMetadata
+	layersByKey       map[attributeKey]int `enc:"-"` // aka, by attribute and breed
+	attributesByName  map[string]int       `enc:"-"`
 }
 
 func (lt *LayersOfType) Init() {
@@ -462,17 +661,85 @@ func (lt *LayersOfType) get(key attributeKey) (*Layer, bool) {
 //		2. within each part, the hash pair consists of;
 //			[0] representing the layer "area".
 //			[1] representing the layer "outline".
+// Field "Frames" contains the parts of the animation frames that follow the
+// first frame ("Parts"). Images missing from a frame are taken from "Parts".
 type Layer struct {
 	OfAttribute string
 	OfBreed     string
 	Parts       [][2]cipher.SHA256
+	Frames      []LayerFrame
+}
+
+// LayerFrame contains the parts of an animation frame of a layer.
+type LayerFrame struct {
+	Parts [][2]cipher.SHA256
+}
+
+// FrameCount returns the number of animation frames of the layer.
+func (a *Layer) FrameCount() int {
+	return len(a.Frames) + 1
+}
+
+// ensurePartsCount ensures the given frame has at least n parts, and returns
+// the parts of the frame.
+func (a *Layer) ensurePartsCount(frame, n int) [][2]cipher.SHA256 {
+	parts := &a.Parts
+	if frame > 0 {
+		if len(a.Frames) < frame {
+			a.Frames = append(a.Frames,
+				make([]LayerFrame, frame-len(a.Frames))...)
+		}
+		parts = &a.Frames[frame-1].Parts
+	}
+	if len(*parts) < n {
+		*parts = append(*parts,
+			make([][2]cipher.SHA256, n-len(*parts))...)
+	}
+	return *parts
+}
+
+// getPair obtains the image hash pair of the given part and frame.
+func (a *Layer) getPair(frame, part int) [2]cipher.SHA256 {
+	var pair [2]cipher.SHA256
+	if part < len(a.Parts) {
+		pair = a.Parts[part]
+	}
+	if frame %= a.FrameCount(); frame > 0 && part < len(a.Frames[frame-1].Parts) {
+		for i, hash := range a.Frames[frame-1].Parts[part] {
+			if hash != (cipher.SHA256{}) {
+				pair[i] = hash
+			}
+		}
+	}
+	return pair
 }
 
-func (a *Layer) ensurePartsCount(n int) {
-	if len(a.Parts) < n {
-		a.Parts = append(a.Parts,
-			make([][2]cipher.SHA256, n-len(a.Parts))...)
+func (a *Layer) partsCount() int {
+	n := len(a.Parts)
+	for _, f := range a.Frames {
+		if len(f.Parts) > n {
+			n = len(f.Parts)
+		}
+	}
+	return n
+}
+
+// hasArea determines whether any frame of the given parts (all if none are
+// given) has an area image.
+func (a *Layer) hasArea(ps ...int) bool {
+	if len(ps) == 0 {
+		for i := 0; i < a.partsCount(); i++ {
+			ps = append(ps, i)
+		}
+	}
+	for f := 0; f < a.FrameCount(); f++ {
+		for _, i := range ps {
+			if a.getPair(f, i)[0] != (cipher.SHA256{}) {
+				return true
+			}
+		}
 	}
+	return false
 }
 
 func (a *Layer) key() attributeKey {
@@ -485,11 +752,12 @@ func newAttributeKey(attribute, breed string) attributeKey {
 	return attributeKey(attribute + "_" + breed)
 }
 
-type layerPartAction func(i int, areaImg, outlineImg image.Image)
+type layerPartAction func(i int, areaImg, outlineImg image.Image) error
 
-func (a *Layer) rangeParts(ic container.Images, action layerPartAction) error {
+func (a *Layer) rangeParts(ic container.Images, frame int, action layerPartAction) error {
 	var e error
-	for i, pair := range a.Parts {
+	for i := 0; i < a.partsCount(); i++ {
+		pair := a.getPair(frame, i)
 		var areaImg image.Image
 		if pair[0] != (cipher.SHA256{}) {
 			if areaImg, e = common.GetImage(ic, pair[0]); e != nil {
@@ -502,16 +770,21 @@ func (a *Layer) rangeParts(ic container.Images, action layerPartAction) error {
 				return e
 			}
 		}
-		action(i, areaImg, outlineImg)
+		if e := action(i, areaImg, outlineImg); e != nil {
+			return e
+		}
 	}
 	return nil
 }
 
-func (a *Layer) generateImage(ic container.Images, bg image.Image, ps ...int) (image.Image, error) {
+// generateImage generates the given frame of the layer image with the given
+// fill (bg), and transformation applied to the area and outline images (if
+// not nil).
+func (a *Layer) generateImage(ic container.Images, bg image.Image, frame int, shift *layer.Transformation, ps ...int) (image.Image, error) {
 	// partsMap informs of which parts are to be included in the generated image.
 	var partsMap = make(map[int]bool)
 	if len(ps) == 0 {
-		for i := 0; i < len(a.Parts); i++ {
+		for i := 0; i < a.partsCount(); i++ {
 			partsMap[i] = true
 		}
 	} else {
@@ -521,15 +794,31 @@ func (a *Layer) generateImage(ic container.Images, bg image.Image, ps ...int) (i
 	}
 
 	out := image.NewRGBA(image.Rect(0, 0, common.XpxLen, common.YpxLen))
-	e := a.rangeParts(ic, func(i int, areaImg, outlineImg image.Image) {
-		if partsMap[i] {
-			if areaImg != nil {
-				common.DrawArea(out, bg, areaImg)
+	e := a.rangeParts(ic, frame, func(i int, areaImg, outlineImg image.Image) error {
+		if !partsMap[i] {
+			return nil
+		}
+		var e error
+		if areaImg != nil {
+			if shift != nil {
+				if areaImg, e = layer.Transform(areaImg, shift); e != nil {
+					return e
+				}
 			}
-			if outlineImg != nil {
-				common.DrawOutline(out, outlineImg)
+			if bg == nil {
+				return fmt.Errorf("part %d has an area but no fill is drawn", i)
 			}
+			common.DrawArea(out, bg, areaImg)
 		}
+		if outlineImg != nil {
+			if shift != nil {
+				if outlineImg, e = layer.Transform(outlineImg, shift); e != nil {
+					return e
+				}
+			}
+			common.DrawOutline(out, outlineImg)
+		}
+		return nil
 	})
 	if e != nil {
 		return nil, e
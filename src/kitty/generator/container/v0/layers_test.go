@@ -0,0 +1,245 @@
+package v0
+
+import (
+	"bytes"
+	"github.com/kittycash/kittiverse/src/kitty/generator/container"
+	"github.com/kittycash/kittiverse/src/kitty/generator/container/common"
+	"github.com/kittycash/kittiverse/src/kitty/genetics"
+	"github.com/kittycash/kittiverse/src/kitty/graphics"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var (
+	testPNGOnce sync.Once
+	testPNG     []byte
+)
+
+// testImage returns a PNG of kitty size with an opaque square in the middle.
+func testImage() []byte {
+	testPNGOnce.Do(func() {
+		testPNG = testImageOf(image.Rect(common.XpxLen/4, common.YpxLen/4, common.XpxLen*3/4, common.YpxLen*3/4))
+	})
+	return testPNG
+}
+
+// testImageOf returns a PNG of kitty size, which is opaque within the
+// rectangle.
+func testImageOf(r image.Rectangle) []byte {
+	return testImageOfColor(r, color.NRGBA{R: 200, G: 100, B: 50, A: 255})
+}
+
+// testImageOfColor returns a PNG of kitty size, which is of the colour within
+// the rectangle.
+func testImageOfColor(r image.Rectangle, c color.NRGBA) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, common.XpxLen, common.YpxLen))
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if e := png.Encode(&buf, img); e != nil {
+		panic(e)
+	}
+	return buf.Bytes()
+}
+
+// testFiles returns loose files of a layer of each default render step, of
+// breed "default" and attribute "a", and of the given extra files. Files of
+// empty content are ".png" images (see testImage).
+func testFiles(extra map[string]string) map[string]string {
+	files := map[string]string{
+		"bodyColorA/default/a.png":       "",
+		"bodyColorB/default/a.png":       "",
+		"bodyPattern/default/a_area.png": "",
+		"ears/default/a_outline.png":     "",
+		"tail/default/a_outline.png":     "",
+		"body/default/a_outline.png":     "",
+		"nose/default/a_outline.png":     "",
+		"eyesColor/default/a.png":        "",
+		"eyes/default/a_outline.png":     "",
+	}
+	for name, content := range extra {
+		files[name] = content
+	}
+	return files
+}
+
+// writeTestFiles writes the loose files into a new temporary directory.
+func writeTestFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		fullPath := path.Join(dir, name)
+		if e := os.MkdirAll(path.Dir(fullPath), 0755); e != nil {
+			t.Fatal(e)
+		}
+		data := []byte(content)
+		if content == "" && strings.HasSuffix(name, ".png") {
+			data = testImage()
+		}
+		if e := ioutil.WriteFile(fullPath, data, 0644); e != nil {
+			t.Fatal(e)
+		}
+	}
+	return dir
+}
+
+// compileTestLayers compiles the loose files (see testFiles).
+func compileTestLayers(t *testing.T, files map[string]string) (*Layers, *Images, *container.Report) {
+	var (
+		lc     = NewLayersContainer()
+		ic     = NewImagesContainer()
+		report = new(container.Report)
+	)
+	if e := lc.CompileWithReport(writeTestFiles(t, files), ic, report); e != nil {
+		t.Fatal(e)
+	}
+	return lc, ic, report
+}
+
+func TestLayers_GenerateKitty_LayerShift(t *testing.T) {
+	lc, ic, _ := compileTestLayers(t, testFiles(map[string]string{
+		RenderFileName: `[{"canvas": "kitty", "layer_type": "eyes"}]`,
+		BreedsFileName: `[
+			{"breed": "maine_coon", "layer_shifts": [{"layer_type": "eyes", "transformation": {"shift_x": 10, "shift_y": -5}}]},
+			{"breed": "sphynx", "layer_shifts": [{"layer_type": "eyes", "transformation": {"scale_x": 0.5, "scale_y": 0.5}}]}
+		]`,
+		"eyes/persian/a_outline.png":    "",
+		"ears/maine_coon/a_outline.png": "",
+		"ears/sphynx/a_outline.png":     "",
+	}))
+	center := image.Rect(common.XpxLen/4, common.YpxLen/4, common.XpxLen*3/4, common.YpxLen*3/4)
+	cases := []struct {
+		breed string
+		exp   image.Rectangle
+	}{
+		{"default", center},
+		{"persian", center}, // own layer.
+		{"maine_coon", center.Add(image.Pt(10, -5))},
+		{"sphynx", image.Rect(center.Min.X+center.Dx()/4, center.Min.Y+center.Dy()/4,
+			center.Max.X-center.Dx()/4, center.Max.Y-center.Dy()/4)},
+	}
+	for _, c := range cases {
+		dna, e := lc.AttributeDNA(genetics.DNA{}, "eyes", c.breed, "a")
+		if e != nil {
+			t.Fatal(e)
+		}
+		img, e := lc.GenerateKitty(ic, dna)
+		if e != nil {
+			t.Fatalf("%s: %v", c.breed, e)
+		}
+		if got := layer.OpaqueBounds(img); got != c.exp {
+			t.Errorf("%s: expected opaque bounds %v, got %v", c.breed, c.exp, got)
+		}
+	}
+}
+
+func TestLayers_GenerateKittyFrames(t *testing.T) {
+	var (
+		corner = image.Rect(0, 0, 100, 100)
+		center = image.Rect(common.XpxLen/4, common.YpxLen/4, common.XpxLen*3/4, common.YpxLen*3/4)
+	)
+	lc, ic, _ := compileTestLayers(t, testFiles(map[string]string{
+		RenderFileName: `[
+			{"canvas": "kitty", "layer_type": "eyes"},
+			{"canvas": "kitty", "layer_type": "ears"}
+		]`,
+		// Frame 2 of the eyes takes part A from frame 0.
+		"eyes/default/a_partB_outline.png":        string(testImageOf(corner.Add(image.Pt(200, 0)))),
+		"eyes/default/a_frame1_outline.png":       string(testImageOf(corner)),
+		"eyes/default/a_frame2_partB_outline.png": string(testImageOf(corner.Add(image.Pt(0, 200)))),
+	}))
+	frames, e := lc.GenerateKittyFrames(ic, genetics.DNA{})
+	if e != nil {
+		t.Fatal(e)
+	}
+	exp := []image.Rectangle{
+		center.Union(corner.Add(image.Pt(200, 0))),
+		corner.Union(corner.Add(image.Pt(200, 0))).Union(center), // ears are still.
+		center.Union(corner.Add(image.Pt(0, 200))),
+	}
+	if len(frames) != len(exp) {
+		t.Fatalf("expected %d frames, got %d", len(exp), len(frames))
+	}
+	for i, frame := range frames {
+		if got := layer.OpaqueBounds(frame); got != exp[i] {
+			t.Errorf("frame %d: expected opaque bounds %v, got %v", i, exp[i], got)
+		}
+	}
+
+	// A single frame is generated as a still image.
+	img, e := lc.GenerateKitty(ic, genetics.DNA{})
+	if e != nil {
+		t.Fatal(e)
+	}
+	if got := layer.OpaqueBounds(img); got != exp[0] {
+		t.Errorf("still: expected opaque bounds %v, got %v", exp[0], got)
+	}
+}
+
+func TestLayers_GenerateKitty_Upgrade(t *testing.T) {
+	var (
+		blue   = string(testImageOfColor(image.Rect(0, 0, common.XpxLen, common.YpxLen), color.NRGBA{B: 255, A: 255}))
+		corner = string(testImageOf(image.Rect(0, 0, 10, 10)))
+	)
+	// Nose colours are those of the fur (as of body colour A), and neither the
+	// pattern nor the outlines drawn over the nose hide its fill.
+	lc, ic, _ := compileTestLayers(t, testFiles(map[string]string{
+		"bodyColorA/default/b.png":       blue,
+		"noseColor/default/a.png":        "",
+		"noseColor/default/b.png":        blue,
+		"bodyPattern/default/a_area.png": corner,
+		"nose/default/a_area.png":        "",
+		"nose/default/a_outline.png":     corner,
+		"eyes/default/a_outline.png":     corner,
+	}))
+	var dna genetics.DNA
+	b := genetics.NewAlleleFromUint16(1)
+	dna.SetGenotype(genetics.DNABodyColorAPos, b, b, b)
+	before, e := lc.GenerateKitty(ic, dna)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	up, e := genetics.Upgrade(dna, genetics.DNAVersion)
+	if e != nil {
+		t.Fatal(e)
+	}
+	after, e := lc.GenerateKitty(ic, up)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if !sameImage(before, after) {
+		t.Error("upgraded kitty does not look the same")
+	}
+
+	// A nose colour other than the fur's looks different.
+	up.SetGenotype(genetics.DNANoseColorPos, genetics.Allele{}, genetics.Allele{}, genetics.Allele{})
+	if other, _ := lc.GenerateKitty(ic, up); sameImage(before, other) {
+		t.Error("expected nose colour to change the kitty")
+	}
+}
+
+func sameImage(a, b image.Image) bool {
+	if a.Bounds() != b.Bounds() {
+		return false
+	}
+	for y := a.Bounds().Min.Y; y < a.Bounds().Max.Y; y++ {
+		for x := a.Bounds().Min.X; x < a.Bounds().Max.X; x++ {
+			r1, g1, b1, a1 := a.At(x, y).RGBA()
+			r2, g2, b2, a2 := b.At(x, y).RGBA()
+			if r1 != r2 || g1 != g2 || b1 != b2 || a1 != a2 {
+				return false
+			}
+		}
+	}
+	return true
+}
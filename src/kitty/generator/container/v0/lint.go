@@ -0,0 +1,96 @@
+package v0
+
+import (
+	"bytes"
+	"github.com/kittycash/kittiverse/src/kitty/generator/container"
+	"github.com/kittycash/kittiverse/src/kitty/generator/container/common"
+	"github.com/skycoin/skycoin/src/cipher"
+	"image/color"
+	"image/png"
+	"sort"
+	"strings"
+)
+
+// checkLayerImage checks that the raw image is a PNG of RGBA color and the
+// expected size.
+func checkLayerImage(report *container.Report, issue container.Issue, raw []byte) {
+	if report == nil {
+		return
+	}
+	cfg, e := png.DecodeConfig(bytes.NewReader(raw))
+	if e != nil {
+		report.Errorf(issue, "failed to decode PNG: %v", e)
+		return
+	}
+	if cfg.Width != common.XpxLen || cfg.Height != common.YpxLen {
+		report.Errorf(issue, "image is %dx%d, expected %dx%d",
+			cfg.Width, cfg.Height, common.XpxLen, common.YpxLen)
+	}
+	if cfg.ColorModel != color.NRGBAModel && cfg.ColorModel != color.NRGBA64Model {
+		report.Errorf(issue, "image is not of RGBA color")
+	}
+}
+
+// lintLayers checks the compiled layers for parts with missing images, and
+// attributes that cannot be rendered for all breeds. Areas without outlines are
+// only reported for layer types that have outlines (i.e. not of patterns).
+func lintLayers(lc *Layers, report *container.Report) {
+	if report == nil {
+		return
+	}
+	for i := range lc.LayerTypes {
+		var (
+			lt       = &lc.LayerTypes[i]
+			areaOnly = !hasOutline(lt)
+		)
+		for j := range lt.Layers {
+			l := &lt.Layers[j]
+			issue := container.Issue{
+				LayerType: lt.OfType,
+				Breed:     l.OfBreed,
+				Attribute: l.OfAttribute,
+			}
+			for part, pair := range l.Parts {
+				switch {
+				case pair[0] == cipher.SHA256{} && pair[1] == cipher.SHA256{}:
+					report.Errorf(issue, "part %c is missing (part indices have a gap)", 'A'+part)
+				case pair[1] == cipher.SHA256{} && !areaOnly:
+					report.Warnf(issue, "part %c has an area without an outline", 'A'+part)
+				}
+			}
+			for f, frame := range l.Frames {
+				if len(frame.Parts) == 0 {
+					report.Warnf(issue, "frame %d has no images (frame indices have a gap)", f+1)
+				}
+			}
+		}
+		for _, attribute := range lt.Attributes {
+			var missing []string
+			for _, breed := range lc.Breeds {
+				if lc.findLayer(lt, breed, attribute) == nil {
+					missing = append(missing, breed)
+				}
+			}
+			if len(missing) > 0 {
+				sort.Strings(missing)
+				report.Errorf(container.Issue{LayerType: lt.OfType, Attribute: attribute},
+					"no '%s' layer to fall back to for breeds: %s",
+					BreedDefault, strings.Join(missing, ", "))
+			}
+		}
+	}
+}
+
+// hasOutline determines whether any layer of the layer type has an outline.
+func hasOutline(lt *LayersOfType) bool {
+	for _, l := range lt.Layers {
+		for f := 0; f < l.FrameCount(); f++ {
+			for part := 0; part < l.partsCount(); part++ {
+				if l.getPair(f, part)[1] != (cipher.SHA256{}) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
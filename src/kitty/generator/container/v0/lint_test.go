@@ -0,0 +1,84 @@
+package v0
+
+import (
+	"bytes"
+	"github.com/kittycash/kittiverse/src/kitty/generator/container"
+	"github.com/kittycash/kittiverse/src/kitty/generator/container/common"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// encodeTestPNG encodes the image as a PNG.
+func encodeTestPNG(img image.Image) string {
+	var buf bytes.Buffer
+	if e := png.Encode(&buf, img); e != nil {
+		panic(e)
+	}
+	return buf.String()
+}
+
+func TestLintLayers(t *testing.T) {
+	cases := []struct {
+		name     string
+		extra    map[string]string
+		severity container.Severity
+		breed    string
+		expMsg   string // empty if no issue is expected.
+	}{
+		{"no issues", nil, "", "", ""},
+		{"part gap", map[string]string{
+			"eyes/default/a_partC_outline.png": "",
+		}, container.SeverityError, "default", "part B is missing"},
+		{"area without outline", map[string]string{
+			"eyes/default/b_area.png": "",
+		}, container.SeverityWarning, "default", "part A has an area without an outline"},
+		{"area of area-only layer type", map[string]string{
+			"bodyPattern/default/b_area.png": "",
+		}, "", "", ""},
+		{"frame gap", map[string]string{
+			"eyes/default/a_frame2_outline.png": "",
+		}, container.SeverityWarning, "default", "frame 1 has no images"},
+		{"no default fallback", map[string]string{
+			"eyes/persian/b_outline.png": "",
+		}, container.SeverityError, "", "no 'default' layer to fall back to for breeds: default"},
+		{"wrong image size", map[string]string{
+			"eyes/default/b_outline.png": encodeTestPNG(image.NewNRGBA(image.Rect(0, 0, 10, 10))),
+		}, container.SeverityError, "default", "image is 10x10"},
+		{"not RGBA", map[string]string{
+			"eyes/default/b_outline.png": encodeTestPNG(image.NewGray(image.Rect(0, 0, common.XpxLen, common.YpxLen))),
+		}, container.SeverityError, "default", "image is not of RGBA color"},
+		{"not PNG", map[string]string{
+			"eyes/default/b_outline.png": "not a png",
+		}, container.SeverityError, "default", "failed to decode PNG"},
+	}
+	for _, c := range cases {
+		_, _, report := compileTestLayers(t, testFiles(c.extra))
+		issues := report.Issues
+		if c.expMsg == "" {
+			if len(issues) > 0 {
+				t.Errorf("%s: unexpected issues %v", c.name, issues)
+			}
+			continue
+		}
+		if len(issues) != 1 || issues[0].Severity != c.severity || issues[0].Breed != c.breed ||
+			!strings.Contains(issues[0].Message, c.expMsg) {
+			t.Errorf("%s: expected %s '%s', got %v", c.name, c.severity, c.expMsg, issues)
+		}
+	}
+}
+
+func TestLayers_CompileWithReport_Strict(t *testing.T) {
+	dir := writeTestFiles(t, testFiles(map[string]string{
+		"eyes/default/a_partC_outline.png": "",
+	}))
+	report := new(container.Report)
+	if e := NewLayersContainer().CompileWithReport(dir, NewImagesContainer(), report); e != nil {
+		t.Errorf("expected no error when not strict, got %v", e)
+	}
+	report = &container.Report{Strict: true}
+	if e := NewLayersContainer().CompileWithReport(dir, NewImagesContainer(), report); e == nil {
+		t.Error("expected error when strict")
+	}
+}
@@ -0,0 +1,269 @@
+package v0
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/kittycash/kittiverse/src/kitty/generator/container"
+	"github.com/kittycash/kittiverse/src/kitty/generator/container/common"
+	"github.com/kittycash/kittiverse/src/kitty/genetics"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"path"
+	"strings"
+)
+
+// Manifest explicitly lists the contents of a generation file, as an
+// alternative to deriving them from the names of loose files. Image paths are
+// relative to the directory of the manifest (unless absolute). Manifests are
+// JSON, or YAML of the same fields.
+//
+// Layers, attributes and breeds are added in the order listed. Accessory
+// slots, breed configs, render steps and dominance are as in
+// "accessories.json", "breeds.json", "render.json" and "dominance.json" (the
+// default accessory slots and render steps are used if none are listed).
+type Manifest struct {
+	Breeds         []ManifestBreed         `json:"breeds,omitempty"`
+	LayerTypes     []ManifestLayerType     `json:"layer_types"`
+	AccessorySlots []string                `json:"accessory_slots,omitempty"`
+	BreedConfigs   []BreedConfig           `json:"breed_configs,omitempty"`
+	RenderSteps    []RenderStep            `json:"render_steps,omitempty"`
+	Backgrounds    []ManifestBackground    `json:"backgrounds,omitempty"`
+	Dominance      genetics.DominanceModel `json:"dominance,omitempty"`
+}
+
+// ManifestBreed declares a breed. Breeds of layers need not be declared, and
+// metadata of breeds is that of their breed configs.
+type ManifestBreed struct {
+	Name string `json:"name"`
+}
+
+type ManifestLayerType struct {
+	Name       string              `json:"name"`
+	Attributes []ManifestAttribute `json:"attributes"`
+}
+
+type ManifestAttribute struct {
+	Name     string              `json:"name"`
+	Metadata *container.Metadata `json:"metadata,omitempty"`
+	Layers   []ManifestLayer     `json:"layers"`
+}
+
+// ManifestLayer is the layer of an attribute for a breed. Field "Frames"
+// contains the parts of the animation frames that follow the first frame.
+type ManifestLayer struct {
+	Breed  string           `json:"breed"`
+	Parts  []ManifestPart   `json:"parts"`
+	Frames [][]ManifestPart `json:"frames,omitempty"`
+}
+
+// ManifestPart contains the image paths of the area and outline of a part
+// (either may be empty).
+type ManifestPart struct {
+	Area    string `json:"area,omitempty"`
+	Outline string `json:"outline,omitempty"`
+}
+
+type ManifestBackground struct {
+	Name  string `json:"name"`
+	Image string `json:"image"`
+}
+
+// ReadManifest reads a manifest from a JSON file, or a YAML file if of the
+// ".yaml" or ".yml" extension.
+func ReadManifest(fileName string) (*Manifest, error) {
+	data, e := ioutil.ReadFile(fileName)
+	if e != nil {
+		return nil, e
+	}
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".yaml", ".yml":
+		if data, e = yamlToJSON(data); e != nil {
+			return nil, e
+		}
+	}
+	m := new(Manifest)
+	if e := json.Unmarshal(data, m); e != nil {
+		return nil, e
+	}
+	return m, nil
+}
+
+// CompileManifest compiles the layers listed in the manifest file, collecting
+// issues with the listed files into the report (if not nil). A strict report
+// fails the compile if errors are found.
+func (lc *Layers) CompileManifest(fileName string, images container.Images, report *container.Report) error {
+	m, e := ReadManifest(fileName)
+	if e != nil {
+		return e
+	}
+	if e := initManifestLayers(lc, m, path.Dir(fileName), images, report); e != nil {
+		log.WithError(e).Error("failed to initiate layers from manifest")
+		return e
+	}
+	if e := setAccessorySlots(lc, m.AccessorySlots); e != nil {
+		log.WithError(e).Error("failed to initiate accessory slots")
+		return e
+	}
+	if e := setBreedConfigs(lc, m.BreedConfigs); e != nil {
+		log.WithError(e).Error("failed to initiate breed configs")
+		return e
+	}
+	if e := lc.GetAlleleRanges().Check(); e != nil {
+		log.WithError(e).Error("failed to check allele ranges")
+		return e
+	}
+	lintLayers(lc, report)
+	if e := setDominance(lc, m.Dominance); e != nil {
+		log.WithError(e).Error("failed to initiate dominance")
+		return e
+	}
+	if e := setRenderSteps(lc, m.RenderSteps); e != nil {
+		log.WithError(e).Error("failed to initiate render steps")
+		return e
+	}
+	for i, bg := range m.Backgrounds {
+		if bg.Name == "" {
+			return errors.New("manifest: background has no name")
+		}
+		for _, prev := range m.Backgrounds[:i] {
+			if prev.Name == bg.Name {
+				return fmt.Errorf("manifest: background '%s' is listed twice", bg.Name)
+			}
+		}
+		if e := addBackground(lc, images, report, bg.Name, manifestPath(path.Dir(fileName), bg.Image)); e != nil {
+			log.WithError(e).Error("failed to initiate backgrounds")
+			return e
+		}
+	}
+	return report.StrictErr()
+}
+
+/*
+	<<< HELPERS >>>
+*/
+
+// yamlToJSON converts YAML to JSON, so that YAML manifests are decoded as of
+// the JSON field names (and decoders) of the manifest's types.
+func yamlToJSON(data []byte) ([]byte, error) {
+	var v interface{}
+	if e := yaml.Unmarshal(data, &v); e != nil {
+		return nil, e
+	}
+	v, e := jsonValue(v)
+	if e != nil {
+		return nil, e
+	}
+	return json.Marshal(v)
+}
+
+// jsonValue converts a decoded YAML value to one that can be encoded as JSON
+// (of which objects have string keys).
+func jsonValue(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			key, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("manifest: key '%v' is not a string", k)
+			}
+			var e error
+			if out[key], e = jsonValue(val); e != nil {
+				return nil, e
+			}
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, val := range v {
+			var e error
+			if out[i], e = jsonValue(val); e != nil {
+				return nil, e
+			}
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+func initManifestLayers(lc *Layers, m *Manifest, dir string, images container.Images, report *container.Report) error {
+	for _, b := range m.Breeds {
+		if b.Name == "" {
+			return errors.New("manifest: breed has no name")
+		}
+		if e := lc.addBreed(b.Name); e != nil {
+			return fmt.Errorf("manifest: breed '%s': %v", b.Name, e)
+		}
+	}
+	for _, mlt := range m.LayerTypes {
+		if mlt.Name == "" || mlt.Name == BackgroundsDirName {
+			return fmt.Errorf("manifest: invalid layer type name '%s'", mlt.Name)
+		}
+		if e := lc.addLayerType(mlt.Name); e != nil {
+			return fmt.Errorf("manifest: layer type '%s': %v", mlt.Name, e)
+		}
+		lt, _ := lc.getLayerType(mlt.Name)
+
+		for _, ma := range mlt.Attributes {
+			// Attribute names are delimited by '_' within loose file names.
+			if ma.Name == "" || strings.Contains(ma.Name, "_") {
+				return fmt.Errorf("manifest: layer type '%s': invalid attribute name '%s'",
+					lt.OfType, ma.Name)
+			}
+			if e := lt.addAttribute(ma.Name); e != nil {
+				return fmt.Errorf("manifest: layer type '%s': attribute '%s': %v",
+					lt.OfType, ma.Name, e)
+			}
+			if ma.Metadata != nil {
+				setMetadata(&lt.AttributeMetadata, ma.Name, *ma.Metadata)
+			}
+			for _, ml := range ma.Layers {
+				if ml.Breed == "" {
+					return fmt.Errorf("manifest: layer type '%s': attribute '%s': layer has no breed",
+						lt.OfType, ma.Name)
+				}
+				if e := lc.addBreed(ml.Breed); e != nil && e != common.ErrAlreadyExists {
+					return e
+				}
+				issue := container.Issue{
+					LayerType: lt.OfType,
+					Breed:     ml.Breed,
+					Attribute: ma.Name,
+				}
+				if _, has := lt.get(newAttributeKey(ma.Name, ml.Breed)); has {
+					return fmt.Errorf("manifest: layer type '%s': attribute '%s': breed '%s' is listed twice",
+						lt.OfType, ma.Name, ml.Breed)
+				}
+				if len(ml.Parts) == 0 {
+					report.Errorf(issue, "layer has no parts")
+				}
+				layer := lt.getOrAddLayer(Layer{OfAttribute: ma.Name, OfBreed: ml.Breed})
+				for f, parts := range append([][]ManifestPart{ml.Parts}, ml.Frames...) {
+					layer.ensurePartsCount(f, len(parts))
+					for p, part := range parts {
+						for _, img := range []struct {
+							path   string
+							isArea bool
+						}{{part.Area, true}, {part.Outline, false}} {
+							if img.path == "" {
+								continue
+							}
+							issue.Path = manifestPath(dir, img.path)
+							addLayerImage(lt, images, report, issue, f, p, img.isArea)
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func manifestPath(dir, p string) string {
+	if path.IsAbs(p) {
+		return p
+	}
+	return path.Join(dir, p)
+}
@@ -0,0 +1,159 @@
+package v0
+
+import (
+	"bytes"
+	"github.com/kittycash/kittiverse/src/kitty/generator/container"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testManifestJSON = `{
+	"breeds": [{"name": "default"}],
+	"breed_configs": [{"breed": "default", "metadata": {"display_name": "Default"}}],
+	"layer_types": [
+		{"name": "bodyColorA", "attributes": [{"name": "a", "layers": [{"breed": "default", "parts": [{"outline": "img.png"}]}]}]},
+		{"name": "bodyColorB", "attributes": [{"name": "a", "layers": [{"breed": "default", "parts": [{"outline": "img.png"}]}]}]},
+		{"name": "bodyPattern", "attributes": [{"name": "a", "layers": [{"breed": "default", "parts": [{"area": "img.png"}]}]}]},
+		{"name": "ears", "attributes": [{"name": "a", "layers": [{"breed": "default", "parts": [{"outline": "img.png"}]}]}]},
+		{"name": "tail", "attributes": [{"name": "a", "layers": [{"breed": "default", "parts": [{"outline": "img.png"}]}]}]},
+		{"name": "body", "attributes": [{"name": "a", "layers": [{"breed": "default", "parts": [{"outline": "img.png"}]}]}]},
+		{"name": "nose", "attributes": [{"name": "a", "layers": [{"breed": "default", "parts": [{"outline": "img.png"}]}]}]},
+		{"name": "eyesColor", "attributes": [{"name": "a", "layers": [{"breed": "default", "parts": [{"outline": "img.png"}]}]}]},
+		{"name": "eyes", "attributes": [
+			{"name": "round", "metadata": {"rarity": "common"}, "layers": [{"breed": "default", "parts": [{"outline": "img.png"}]}]},
+			{"name": "wink", "layers": [{"breed": "default", "parts": [{"area": "img.png", "outline": "img.png"}]}]}
+		]}
+	],
+	"dominance": {"eyes": {"mode": "ranking", "ranking": ["0001"]}}
+}`
+
+const testManifestYAML = `
+breeds:
+  - name: default
+breed_configs:
+  - breed: default
+    metadata: {display_name: Default}
+layer_types:
+  - {name: bodyColorA, attributes: [{name: a, layers: [{breed: default, parts: [{outline: img.png}]}]}]}
+  - {name: bodyColorB, attributes: [{name: a, layers: [{breed: default, parts: [{outline: img.png}]}]}]}
+  - {name: bodyPattern, attributes: [{name: a, layers: [{breed: default, parts: [{area: img.png}]}]}]}
+  - {name: ears, attributes: [{name: a, layers: [{breed: default, parts: [{outline: img.png}]}]}]}
+  - {name: tail, attributes: [{name: a, layers: [{breed: default, parts: [{outline: img.png}]}]}]}
+  - {name: body, attributes: [{name: a, layers: [{breed: default, parts: [{outline: img.png}]}]}]}
+  - {name: nose, attributes: [{name: a, layers: [{breed: default, parts: [{outline: img.png}]}]}]}
+  - {name: eyesColor, attributes: [{name: a, layers: [{breed: default, parts: [{outline: img.png}]}]}]}
+  - name: eyes
+    attributes:
+      - name: round
+        metadata: {rarity: common}
+        layers: [{breed: default, parts: [{outline: img.png}]}]
+      - name: wink
+        layers: [{breed: default, parts: [{area: img.png, outline: img.png}]}]
+dominance:
+  eyes: {mode: ranking, ranking: ["0001"]}
+`
+
+func TestLayers_CompileManifest(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"img.png":                     "",
+		"manifest.json":               testManifestJSON,
+		"manifest.yaml":               testManifestYAML,
+		"manifest.yml":                testManifestYAML,
+		"invalid.yaml":                "layer_types: [{name: eyes, attributes: {1: a}}]",
+		"no_breed_name.json":          `{"breeds": [{"name": ""}]}`,
+		"duplicate_breed.json":        `{"breeds": [{"name": "default"}, {"name": "default"}]}`,
+		"no_layer_type_name.json":     `{"layer_types": [{"name": ""}]}`,
+		"duplicate_layer_type.json":   `{"layer_types": [{"name": "eyes"}, {"name": "eyes"}]}`,
+		"no_attribute_name.json":      `{"layer_types": [{"name": "eyes", "attributes": [{"name": ""}]}]}`,
+		"invalid_attribute_name.json": `{"layer_types": [{"name": "eyes", "attributes": [{"name": "a_b"}]}]}`,
+		"duplicate_attribute.json":    `{"layer_types": [{"name": "eyes", "attributes": [{"name": "a"}, {"name": "a"}]}]}`,
+		"no_layer_breed.json": `{"layer_types": [{"name": "eyes", "attributes": [
+			{"name": "a", "layers": [{"parts": [{"outline": "img.png"}]}]}
+		]}]}`,
+		"duplicate_layer_breed.json": `{"layer_types": [{"name": "eyes", "attributes": [{"name": "a", "layers": [
+			{"breed": "default", "parts": [{"outline": "img.png"}]},
+			{"breed": "default", "parts": [{"outline": "img.png"}]}
+		]}]}]}`,
+		"no_background_name.json": `{
+			"layer_types": [{"name": "eyes", "attributes": [{"name": "a", "layers": [{"breed": "default", "parts": [{"outline": "img.png"}]}]}]}],
+			"render_steps": [{"canvas": "kitty", "layer_type": "eyes"}],
+			"backgrounds": [{"image": "img.png"}]
+		}`,
+		"unlisted_accessory_slot.json": `{
+			"layer_types": [
+				{"name": "accessory_hat", "attributes": [{"name": "a", "layers": [{"breed": "default", "parts": [{"outline": "img.png"}]}]}]},
+				{"name": "accessory_bow", "attributes": [{"name": "a", "layers": [{"breed": "default", "parts": [{"outline": "img.png"}]}]}]}
+			],
+			"accessory_slots": ["accessory_hat"]
+		}`,
+		"duplicate_background.json": `{
+			"layer_types": [{"name": "eyes", "attributes": [{"name": "a", "layers": [{"breed": "default", "parts": [{"outline": "img.png"}]}]}]}],
+			"render_steps": [{"canvas": "kitty", "layer_type": "eyes"}],
+			"backgrounds": [{"name": "a", "image": "img.png"}, {"name": "a", "image": "img.png"}]
+		}`,
+	})
+	cases := []struct {
+		name    string
+		expFail bool
+		expErr  string // within the error message (if not empty).
+	}{
+		{"manifest.json", false, ""},
+		{"manifest.yaml", false, ""},
+		{"manifest.yml", false, ""},
+		{"invalid.yaml", true, ""},
+		{"missing.json", true, ""},
+		{"no_breed_name.json", true, "breed has no name"},
+		{"duplicate_breed.json", true, "breed 'default'"},
+		{"no_layer_type_name.json", true, "invalid layer type name"},
+		{"duplicate_layer_type.json", true, "layer type 'eyes'"},
+		{"no_attribute_name.json", true, "invalid attribute name"},
+		{"invalid_attribute_name.json", true, "invalid attribute name"},
+		{"duplicate_attribute.json", true, "attribute 'a'"},
+		{"no_layer_breed.json", true, "layer has no breed"},
+		{"duplicate_layer_breed.json", true, "listed twice"},
+		{"no_background_name.json", true, "background has no name"},
+		{"duplicate_background.json", true, "background 'a' is listed twice"},
+		{"unlisted_accessory_slot.json", true, "accessory slot 'accessory_bow' is not listed"},
+	}
+	var exp []byte
+	for _, c := range cases {
+		var (
+			lc     = NewLayersContainer()
+			report = new(container.Report)
+			e      = lc.CompileManifest(path.Join(dir, c.name), NewImagesContainer(), report)
+		)
+		if c.expFail {
+			if e == nil || !strings.Contains(e.Error(), c.expErr) {
+				t.Errorf("%s: expected error '%s', got %v", c.name, c.expErr, e)
+			}
+			continue
+		}
+		if e != nil {
+			t.Fatalf("%s: %v", c.name, e)
+		}
+		for _, issue := range report.Issues {
+			if issue.Severity == container.SeverityError {
+				t.Errorf("%s: unexpected issue %v", c.name, issue)
+			}
+		}
+		lt, _ := lc.getLayerType("eyes")
+		if !reflect.DeepEqual(lt.Attributes, []string{"round", "wink"}) {
+			t.Errorf("%s: expected attributes [round wink], got %v", c.name, lt.Attributes)
+		}
+		if md, _ := lc.GetAttributeMetadata("eyes", "round"); md.Rarity != "common" {
+			t.Errorf("%s: expected rarity 'common', got '%s'", c.name, md.Rarity)
+		}
+		if md, _ := lc.GetBreedMetadata("default"); md.DisplayName != "Default" {
+			t.Errorf("%s: expected display name 'Default', got '%s'", c.name, md.DisplayName)
+		}
+
+		// All formats compile the same layers.
+		if exp == nil {
+			exp = lc.Export()
+		} else if !bytes.Equal(lc.Export(), exp) {
+			t.Errorf("%s: expected the same layers as %s", c.name, cases[0].name)
+		}
+	}
+}
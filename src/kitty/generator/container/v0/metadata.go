@@ -0,0 +1,112 @@
+package v0
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/kittycash/kittiverse/src/kitty/generator/container"
+	"io/ioutil"
+	"path"
+	"strings"
+)
+
+const (
+	// MetadataExt is the extension of the optional sidecar files (within the
+	// directories of layer types) that contain the metadata of attributes,
+	// i.e. "eyes/round.json" for attribute "round" of layer type "eyes". The
+	// metadata of breeds is that of their breed configs (see BreedConfig).
+	MetadataExt = ".json"
+)
+
+// NamedMetadata is the metadata of a named attribute.
+type NamedMetadata struct {
+	Name     string
+	Metadata container.Metadata
+}
+
+// GetBreedMetadata obtains the metadata of the breed, as of its breed config.
+func (lc *Layers) GetBreedMetadata(bName string) (container.Metadata, bool) {
+	bc, ok := lc.getBreedConfig(bName)
+	if !ok || !hasMetadata(bc.Metadata) {
+		return container.Metadata{}, false
+	}
+	return bc.Metadata, true
+}
+
+// GetAttributeMetadata obtains the metadata of the attribute of the layer type.
+func (lc *Layers) GetAttributeMetadata(ltName, attribute string) (container.Metadata, bool) {
+	lt, ok := lc.getLayerType(ltName)
+	if !ok {
+		return container.Metadata{}, false
+	}
+	return getMetadata(lt.AttributeMetadata, attribute)
+}
+
+/*
+	<<< HELPERS >>>
+*/
+
+// initMetadata reads the sidecar metadata of the attributes of each layer
+// type (see MetadataExt).
+func initMetadata(lc *Layers, rootDir string, report *container.Report) error {
+	for i := range lc.LayerTypes {
+		var (
+			lt    = &lc.LayerTypes[i]
+			ltDir = path.Join(rootDir, lt.OfType)
+		)
+		files, e := ioutil.ReadDir(ltDir)
+		if e != nil {
+			return e
+		}
+		for _, file := range files {
+			if file.IsDir() || path.Ext(file.Name()) != MetadataExt {
+				continue
+			}
+			var (
+				fullPath  = path.Join(ltDir, file.Name())
+				attribute = strings.TrimSuffix(file.Name(), MetadataExt)
+				issue     = container.Issue{Path: fullPath, LayerType: lt.OfType, Attribute: attribute}
+			)
+			if _, has := lt.attributesByName[attribute]; !has {
+				report.Warnf(issue, "metadata of attribute '%s' of layer type '%s' which does not exist",
+					attribute, lt.OfType)
+				continue
+			}
+			data, e := ioutil.ReadFile(fullPath)
+			if e != nil {
+				return e
+			}
+			var md container.Metadata
+			if e := json.Unmarshal(data, &md); e != nil {
+				return fmt.Errorf("metadata of attribute '%s' of layer type '%s': %v",
+					attribute, lt.OfType, e)
+			}
+			setMetadata(&lt.AttributeMetadata, attribute, md)
+		}
+	}
+	return nil
+}
+
+func hasMetadata(md container.Metadata) bool {
+	return md.DisplayName != "" || md.Description != "" || md.Rarity != "" ||
+		md.Artist != "" || len(md.Tags) > 0
+}
+
+func getMetadata(list []NamedMetadata, name string) (container.Metadata, bool) {
+	for _, v := range list {
+		if v.Name == name {
+			return v.Metadata, true
+		}
+	}
+	return container.Metadata{}, false
+}
+
+// setMetadata sets the metadata of the given name within the list.
+func setMetadata(list *[]NamedMetadata, name string, md container.Metadata) {
+	for i := range *list {
+		if (*list)[i].Name == name {
+			(*list)[i].Metadata = md
+			return
+		}
+	}
+	*list = append(*list, NamedMetadata{Name: name, Metadata: md})
+}
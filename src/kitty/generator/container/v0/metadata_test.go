@@ -0,0 +1,89 @@
+package v0
+
+import (
+	"github.com/kittycash/kittiverse/src/kitty/generator/container"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLayers_Metadata(t *testing.T) {
+	lc, _, report := compileTestLayers(t, testFiles(map[string]string{
+		BreedsFileName: `[
+			{"breed": "default", "metadata": {"display_name": "Default", "tags": ["plain"]}},
+			{"breed": "persian", "parent": "default"}
+		]`,
+		"eyes/a" + MetadataExt:         `{"display_name": "Round", "rarity": "common", "artist": "someone"}`,
+		"eyes/b" + MetadataExt:         `{"display_name": "Missing"}`,
+		"eyes/c" + MetadataExt:         `{"display_name": "Missing"}`,
+		"ears/default/a" + MetadataExt: `{"display_name": "Not a sidecar"}`,
+	}))
+	cases := []struct {
+		layerType string // empty for breeds.
+		name      string
+		exp       container.Metadata
+		expOK     bool
+	}{
+		{"", "default", container.Metadata{DisplayName: "Default", Tags: []string{"plain"}}, true},
+		{"", "persian", container.Metadata{}, false},
+		{"", "sphynx", container.Metadata{}, false},
+		{"eyes", "a", container.Metadata{DisplayName: "Round", Rarity: "common", Artist: "someone"}, true},
+		{"eyes", "b", container.Metadata{}, false},
+		{"ears", "a", container.Metadata{}, false},
+		{"hat", "a", container.Metadata{}, false},
+	}
+	for _, c := range cases {
+		var (
+			md container.Metadata
+			ok bool
+		)
+		if c.layerType == "" {
+			md, ok = lc.GetBreedMetadata(c.name)
+		} else {
+			md, ok = lc.GetAttributeMetadata(c.layerType, c.name)
+		}
+		if ok != c.expOK || !reflect.DeepEqual(md, c.exp) {
+			t.Errorf("%s '%s': expected %+v (%v), got %+v (%v)", c.layerType, c.name, c.exp, c.expOK, md, ok)
+		}
+	}
+
+	// Metadata of what does not exist is reported, in order.
+	var warnings []string
+	for _, issue := range report.Issues {
+		if strings.HasPrefix(issue.Message, "metadata of") {
+			warnings = append(warnings, issue.Message)
+			if issue.Severity != container.SeverityWarning {
+				t.Errorf("expected warning, got %v", issue)
+			}
+		}
+	}
+	expWarnings := []string{
+		"metadata of attribute 'b' of layer type 'eyes' which does not exist",
+		"metadata of attribute 'c' of layer type 'eyes' which does not exist",
+	}
+	if !reflect.DeepEqual(warnings, expWarnings) {
+		t.Errorf("expected warnings %q, got %q", expWarnings, warnings)
+	}
+
+	// Metadata is kept by the container.
+	imported := NewLayersContainer()
+	if e := imported.Import(lc.Export()); e != nil {
+		t.Fatal(e)
+	}
+	if !reflect.DeepEqual(imported.BreedConfigs, lc.BreedConfigs) {
+		t.Errorf("expected imported breed configs %+v, got %+v", lc.BreedConfigs, imported.BreedConfigs)
+	}
+	for i := range lc.LayerTypes {
+		exp, got := lc.LayerTypes[i].AttributeMetadata, imported.LayerTypes[i].AttributeMetadata
+		if !reflect.DeepEqual(got, exp) {
+			t.Errorf("%s: expected imported metadata %+v, got %+v", lc.LayerTypes[i].OfType, exp, got)
+		}
+	}
+
+	// Invalid metadata fails to compile.
+	lc = NewLayersContainer()
+	dir := writeTestFiles(t, testFiles(map[string]string{"eyes/a" + MetadataExt: `{"tags": "plain"}`}))
+	if e := lc.CompileWithReport(dir, NewImagesContainer(), nil); e == nil {
+		t.Error("expected error of invalid metadata")
+	}
+}
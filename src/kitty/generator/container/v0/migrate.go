@@ -0,0 +1,50 @@
+package v0
+
+import (
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/encoder"
+)
+
+// layersV0 is the layout of layers of version 0, which predates render steps.
+type layersV0 struct {
+	LayerTypes []layersOfTypeV0
+	Breeds     []string
+}
+
+type layersOfTypeV0 struct {
+	OfType     string
+	Layers     []layerV0
+	Attributes []string
+}
+
+type layerV0 struct {
+	OfAttribute string
+	OfBreed     string
+	Parts       [][2]cipher.SHA256
+}
+
+// importLayersV0 imports layers of version 0 (without the version prefix).
+// The accessory slots and render steps are the defaults.
+func importLayersV0(lc *Layers, raw []byte) error {
+	var old layersV0
+	if e := encoder.DeserializeRaw(raw, &old); e != nil {
+		return e
+	}
+	*lc = Layers{Breeds: old.Breeds}
+	for _, oldLT := range old.LayerTypes {
+		lt := LayersOfType{OfType: oldLT.OfType, Attributes: oldLT.Attributes}
+		for _, l := range oldLT.Layers {
+			lt.Layers = append(lt.Layers, Layer{
+				OfAttribute: l.OfAttribute,
+				OfBreed:     l.OfBreed,
+				Parts:       l.Parts,
+			})
+		}
+		lc.LayerTypes = append(lc.LayerTypes, lt)
+	}
+	lc.prepareMaps()
+	if e := setAccessorySlots(lc, nil); e != nil {
+		return e
+	}
+	return setRenderSteps(lc, nil)
+}
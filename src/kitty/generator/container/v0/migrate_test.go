@@ -0,0 +1,73 @@
+package v0
+
+import (
+	"github.com/kittycash/kittiverse/src/kitty/generator/container/common"
+	"github.com/kittycash/kittiverse/src/kitty/genetics"
+	"github.com/skycoin/skycoin/src/cipher/encoder"
+	"image"
+	"reflect"
+	"testing"
+)
+
+// exportV0 exports the layers in the layout of version 0.
+func exportV0(lc *Layers) []byte {
+	var old layersV0
+	old.Breeds = lc.Breeds
+	for _, lt := range lc.LayerTypes {
+		oldLT := layersOfTypeV0{OfType: lt.OfType, Attributes: lt.Attributes}
+		for _, l := range lt.Layers {
+			oldLT.Layers = append(oldLT.Layers, layerV0{
+				OfAttribute: l.OfAttribute,
+				OfBreed:     l.OfBreed,
+				Parts:       l.Parts,
+			})
+		}
+		old.LayerTypes = append(old.LayerTypes, oldLT)
+	}
+	return append(encoder.Serialize(uint16(0)), encoder.Serialize(old)...)
+}
+
+func TestLayers_ImportV0(t *testing.T) {
+	lc, ic, _ := compileTestLayers(t, testFiles(map[string]string{
+		"accessory_hat/default/a.png": "",
+		"eyes/persian/b_outline.png":  "",
+	}))
+
+	imported := NewLayersContainer()
+	if e := imported.Import(exportV0(lc)); e != nil {
+		t.Fatal(e)
+	}
+	if !reflect.DeepEqual(imported.RenderSteps, lc.RenderSteps) {
+		t.Errorf("expected default render steps %v, got %v", lc.RenderSteps, imported.RenderSteps)
+	}
+	if !reflect.DeepEqual(imported.Breeds, lc.Breeds) {
+		t.Errorf("expected breeds %v, got %v", lc.Breeds, imported.Breeds)
+	}
+
+	exp, e := lc.GenerateKitty(ic, genetics.DNA{})
+	if e != nil {
+		t.Fatal(e)
+	}
+	got, e := imported.GenerateKitty(ic, genetics.DNA{})
+	if e != nil {
+		t.Fatal(e)
+	}
+	if !reflect.DeepEqual(exp.(*image.RGBA).Pix, got.(*image.RGBA).Pix) {
+		t.Error("expected imported layers to generate the same kitty")
+	}
+
+	// The current layout round-trips.
+	again := NewLayersContainer()
+	if e := again.Import(lc.Export()); e != nil {
+		t.Fatal(e)
+	}
+	if !reflect.DeepEqual(again.RenderSteps, lc.RenderSteps) {
+		t.Errorf("expected render steps %v, got %v", lc.RenderSteps, again.RenderSteps)
+	}
+
+	// Newer versions are rejected.
+	raw := append(encoder.Serialize(version+1), encoder.Serialize(lc)...)
+	if e := NewLayersContainer().Import(raw); e != common.ErrInvalidVersion {
+		t.Errorf("expected %v, got %v", common.ErrInvalidVersion, e)
+	}
+}
@@ -0,0 +1,53 @@
+package v0
+
+import (
+	"github.com/kittycash/kittiverse/src/kitty/generator/container"
+	"github.com/kittycash/kittiverse/src/kitty/genetics"
+)
+
+// GetPhenotype resolves the breed and the attributes of the kitty of given
+// DNA, as selected when generating the kitty.
+func (lc *Layers) GetPhenotype(dna genetics.DNA) (*container.Phenotype, error) {
+	if e := genetics.CheckVersion(dna.Version()); e != nil {
+		return nil, e
+	}
+	breed, e := lc.expressBreed(dna)
+	if e != nil {
+		return nil, e
+	}
+	var (
+		iic = &imgInputCommon{lc: lc, breed: breed, dna: dna}
+		out = &container.Phenotype{
+			Hex:     dna.Hex(),
+			Base58:  dna.Base58(),
+			Version: dna.Version(),
+			Breed:   breed,
+		}
+	)
+	out.BreedMetadata, _ = lc.GetBreedMetadata(breed)
+	for i := range lc.RenderSteps {
+		step := &lc.RenderSteps[i]
+		sel, e := selectLayer(iic, step)
+		if e != nil {
+			return nil, e
+		}
+		if sel == nil {
+			continue
+		}
+		trait := container.Trait{
+			LayerType:  step.LayerType,
+			Attribute:  sel.layer.OfAttribute,
+			LayerBreed: sel.layer.OfBreed,
+			Status:     sel.status,
+		}
+		if pos, ok := genetics.NewDNAPosFromString(step.gene()); ok {
+			a, _ := iic.getAllele(pos, step)
+			trait.Gene = pos.String()
+			trait.Allele = a.Hex()
+			trait.Dominance = string(lc.getDominance(pos).Mode)
+		}
+		trait.Metadata, _ = lc.GetAttributeMetadata(trait.LayerType, trait.Attribute)
+		out.Traits = append(out.Traits, trait)
+	}
+	return out, nil
+}
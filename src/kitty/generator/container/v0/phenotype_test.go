@@ -0,0 +1,126 @@
+package v0
+
+import (
+	"github.com/kittycash/kittiverse/src/kitty/generator/container"
+	"github.com/kittycash/kittiverse/src/kitty/genetics"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestLayers_GetPhenotype(t *testing.T) {
+	lc, _, _ := compileTestLayers(t, testFiles(map[string]string{
+		"eyes/default/b_outline.png": "",
+		"eyes/persian/a_outline.png": "",
+		"eyes/b" + MetadataExt:       `{"rarity": "rare"}`,
+	}))
+	cases := []struct {
+		breed     string
+		attribute string
+		expTrait  container.Trait
+	}{
+		{"default", "a", container.Trait{LayerType: "eyes", Attribute: "a",
+			LayerBreed: "default", Status: container.LayerOwn}},
+		{"persian", "a", container.Trait{LayerType: "eyes", Attribute: "a",
+			LayerBreed: "persian", Status: container.LayerOwn}},
+		{"persian", "b", container.Trait{LayerType: "eyes", Attribute: "b",
+			LayerBreed: "default", Status: container.LayerFallback,
+			Metadata: container.Metadata{Rarity: "rare"}}},
+	}
+	for _, c := range cases {
+		dna, e := lc.AttributeDNA(genetics.DNA{}, "eyes", c.breed, c.attribute)
+		if e != nil {
+			t.Fatal(e)
+		}
+		p, e := lc.GetPhenotype(dna)
+		if e != nil {
+			t.Fatalf("%s of %s: %v", c.attribute, c.breed, e)
+		}
+		if p.Breed != c.breed || p.Hex != dna.Hex() {
+			t.Errorf("%s of %s: expected breed '%s' of %s, got '%s' of %s",
+				c.attribute, c.breed, c.breed, dna.Hex(), p.Breed, p.Hex)
+		}
+		var trait *container.Trait
+		for i := range p.Traits {
+			if p.Traits[i].LayerType == "eyes" {
+				trait = &p.Traits[i]
+			}
+		}
+		if trait == nil {
+			t.Fatalf("%s of %s: expected trait of eyes", c.attribute, c.breed)
+		}
+		allele, _ := lc.GetAllele("eyes", c.attribute)
+		if trait.Gene != "eyes" || trait.Allele != allele.Hex() {
+			t.Errorf("%s of %s: expected allele %s of eyes, got %s of %s",
+				c.attribute, c.breed, allele.Hex(), trait.Allele, trait.Gene)
+		}
+		exp := c.expTrait
+		exp.Gene, exp.Allele, exp.Dominance = trait.Gene, trait.Allele, trait.Dominance
+		if !reflect.DeepEqual(*trait, exp) {
+			t.Errorf("%s of %s: expected trait %+v, got %+v", c.attribute, c.breed, exp, *trait)
+		}
+	}
+
+	// Breed alleles beyond the breeds fail.
+	var dna genetics.DNA
+	b := genetics.NewAlleleFromUint16(uint16(len(lc.Breeds)))
+	dna.SetGenotype(genetics.DNABreedPos, b, b, b)
+	if _, e := lc.GetPhenotype(dna); e == nil {
+		t.Error("expected error of breed index out of range")
+	}
+}
+
+func TestLayers_NovelMutation(t *testing.T) {
+	lc, ic, _ := compileTestLayers(t, testFiles(map[string]string{
+		"eyes/default/b_outline.png": "",
+		"eyes/default/z_outline.png": "", // rare attribute.
+	}))
+	var parent genetics.DNA
+	parent.SetVersion(genetics.DNAVersion)
+
+	cases := []struct {
+		novel   string
+		expFail bool
+	}{
+		{"0002", false},
+		{"0003", true}, // not an attribute.
+	}
+	for _, c := range cases {
+		b := &genetics.Breeder{
+			Ranges: lc.GetAlleleRanges(),
+			Mutations: &genetics.MutationModel{
+				Genes: map[string]genetics.Mutation{
+					"eyes": {Probability: 1, NovelProbability: 1,
+						Novel: &genetics.AlleleRange{Min: c.novel, Max: c.novel}},
+				},
+			},
+			Rand: rand.New(rand.NewSource(1)),
+		}
+		child, e := b.Breed(parent, parent)
+		if c.expFail {
+			if e == nil {
+				t.Errorf("novel %s: expected error", c.novel)
+			}
+			continue
+		}
+		if e != nil {
+			t.Fatalf("novel %s: %v", c.novel, e)
+		}
+		p, e := lc.GetPhenotype(child)
+		if e != nil {
+			t.Fatalf("novel %s: %v", c.novel, e)
+		}
+		var attribute string
+		for _, trait := range p.Traits {
+			if trait.LayerType == "eyes" {
+				attribute = trait.Attribute
+			}
+		}
+		if attribute != "z" {
+			t.Errorf("novel %s: expected eyes attribute 'z', got '%s'", c.novel, attribute)
+		}
+		if _, e := lc.GenerateKitty(ic, child); e != nil {
+			t.Errorf("novel %s: %v", c.novel, e)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package v0
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/kittycash/kittiverse/src/kitty/genetics"
+	"io/ioutil"
+	"os"
+	"path"
+)
+
+const (
+	// RenderFileName is the name of the optional file (within the root
+	// directory of loose files) that describes how kitty layers are composed.
+	RenderFileName = "render.json"
+
+	// CanvasKitty is the name of the canvas that holds the final kitty image.
+	CanvasKitty = "kitty"
+)
+
+// RenderStep describes a single step of composing a kitty image.
+// For each step, a layer of the given layer type is generated and drawn onto
+// the named canvas. Canvases other than "kitty" are intermediate images that
+// can be used as the fill of the areas of layers in later steps.
+//
+// The attribute of the layer is selected by the phenotype of the given gene,
+// which must be a gene expressed by the DNA. When the gene is omitted and no
+// gene is named after the layer type, the layer type must have a single
+// attribute, which is always used (useful for layers such as whiskers, which
+// only vary by breed). A step is skipped when the DNA's version does not express
+// the gene, or when it is optional and the layer type does not exist. Steps
+// that use a skipped canvas as fill will use the fallback canvas instead, so a
+// fill canvas that may be skipped needs a fallback canvas that is drawn for DNA
+// of every version. Layers with areas must have a fill.
+//
+// The phenotype is as of the dominance of the gene (see DominanceFileName). A
+// "secondary" step is selected by the second allele expressed by a codominant
+// gene (i.e. bicolour fur), and is skipped when there is none.
+type RenderStep struct {
+	Canvas       string  `json:"canvas"`                  // canvas to draw onto.
+	LayerType    string  `json:"layer_type"`              // type of layer to generate.
+	Gene         string  `json:"gene,omitempty"`          // gene that selects the attribute (defaults to layer type).
+	Fill         string  `json:"fill,omitempty"`          // canvas used as the fill of areas (optional).
+	FillFallback string  `json:"fill_fallback,omitempty"` // canvas used as fill when "fill" is not drawn (optional).
+	Parts        []int32 `json:"parts,omitempty"`         // parts of the layer to include (defaults to all).
+	Optional     bool    `json:"optional,omitempty"`      // whether the layer type may be missing.
+	Secondary    bool    `json:"secondary,omitempty"`     // whether the secondary allele of a codominant gene selects the attribute.
+}
+
+func (s *RenderStep) gene() string {
+	if s.Gene == "" {
+		return s.LayerType
+	}
+	return s.Gene
+}
+
+func (s *RenderStep) parts() []int {
+	ps := make([]int, len(s.Parts))
+	for i, p := range s.Parts {
+		ps[i] = int(p)
+	}
+	return ps
+}
+
+// DefaultRenderSteps returns the render steps used when the root directory of
+// loose files does not contain a render file. The accessory slots found are
+// drawn after these steps.
+func DefaultRenderSteps() []RenderStep {
+	return []RenderStep{
+		// Fur.
+		{Canvas: "furB", LayerType: genetics.DNABodyColorBPos.String()},
+		{Canvas: "fur", LayerType: genetics.DNABodyColorAPos.String()},
+		{Canvas: "fur", LayerType: genetics.DNABodyPatternPos.String(), Fill: "furB"},
+		// Kitty.
+		{Canvas: CanvasKitty, LayerType: genetics.DNAEarsAttrPos.String(), Fill: "fur"},
+		{Canvas: CanvasKitty, LayerType: genetics.DNATailAttrPos.String(), Fill: "fur"},
+		{Canvas: CanvasKitty, LayerType: genetics.DNABodyAttrPos.String(), Fill: "fur"},
+		{Canvas: "noseColor", LayerType: genetics.DNANoseColorPos.String(), Optional: true},
+		{Canvas: CanvasKitty, LayerType: genetics.DNANoseAttrPos.String(), Fill: "noseColor", FillFallback: "fur"},
+		// Eyes.
+		{Canvas: "eyesColor", LayerType: genetics.DNAEyesColorPos.String()},
+		{Canvas: CanvasKitty, LayerType: genetics.DNAEyesAttrPos.String(), Fill: "eyesColor"},
+	}
+}
+
+/*
+	<<< HELPERS >>>
+*/
+
+func initRenderSteps(lc *Layers, rootDir string) error {
+	data, e := ioutil.ReadFile(path.Join(rootDir, RenderFileName))
+	switch {
+	case os.IsNotExist(e):
+		log.Infof("no '%s' file found, using default render steps", RenderFileName)
+		return setRenderSteps(lc, nil)
+	case e != nil:
+		return e
+	}
+	var steps []RenderStep
+	if e := json.Unmarshal(data, &steps); e != nil {
+		return e
+	}
+	return setRenderSteps(lc, steps)
+}
+
+// setRenderSteps sets and checks the render steps, using the default render
+// steps if none are given.
+func setRenderSteps(lc *Layers, steps []RenderStep) error {
+	if steps == nil {
+		steps = append(DefaultRenderSteps(), accessoryRenderSteps(lc)...)
+	}
+	lc.RenderSteps = steps
+	return checkRenderSteps(lc)
+}
+
+func checkRenderSteps(lc *Layers) error {
+	var (
+		drawn       = map[string]bool{CanvasKitty: true}
+		alwaysDrawn = map[string]bool{CanvasKitty: true}
+	)
+	for i, step := range lc.RenderSteps {
+		if step.Canvas == "" {
+			return fmt.Errorf("render step %d: no canvas specified", i)
+		}
+		if _, has := lc.layerTypesByName[step.LayerType]; !has && !step.Optional {
+			return fmt.Errorf("render step %d: layer type '%s' does not exist",
+				i, step.LayerType)
+		}
+		if e := checkRenderStepGene(lc, &step); e != nil {
+			return fmt.Errorf("render step %d: %v", i, e)
+		}
+		if step.Secondary {
+			pos, ok := genetics.NewDNAPosFromString(step.gene())
+			if !ok || lc.getDominance(pos).Mode != genetics.DominanceCodominant {
+				return fmt.Errorf("render step %d: secondary step of gene '%s' which is not codominant",
+					i, step.gene())
+			}
+		}
+		for _, fill := range []string{step.Fill, step.FillFallback} {
+			if fill != "" && !drawn[fill] {
+				return fmt.Errorf("render step %d: fill canvas '%s' is not drawn by a previous step",
+					i, fill)
+			}
+		}
+		if e := checkRenderStepFill(lc, &step, alwaysDrawn); e != nil {
+			return fmt.Errorf("render step %d: %v", i, e)
+		}
+		drawn[step.Canvas] = true
+		if isAlwaysDrawn(lc, &step) {
+			alwaysDrawn[step.Canvas] = true
+		}
+	}
+	return nil
+}
+
+// checkRenderStepFill ensures the areas of the layers of the render step have
+// a fill for DNA of every version: if the fill canvas may be skipped, the
+// fallback canvas must always be drawn.
+func checkRenderStepFill(lc *Layers, step *RenderStep, alwaysDrawn map[string]bool) error {
+	switch {
+	case step.Fill == "" && step.FillFallback == "":
+		lt, ok := lc.getLayerType(step.LayerType)
+		if !ok {
+			return nil
+		}
+		for _, l := range lt.Layers {
+			if l.hasArea(step.parts()...) {
+				return fmt.Errorf("layer type '%s' has areas but no fill canvas is specified",
+					step.LayerType)
+			}
+		}
+	case !alwaysDrawn[step.Fill] && !alwaysDrawn[step.FillFallback]:
+		return fmt.Errorf("fill canvas '%s' may be skipped and no fallback canvas is always drawn",
+			step.Fill)
+	}
+	return nil
+}
+
+// isAlwaysDrawn determines whether the render step draws a layer for DNA of
+// every version, regardless of its alleles.
+func isAlwaysDrawn(lc *Layers, step *RenderStep) bool {
+	lt, ok := lc.getLayerType(step.LayerType)
+	if !ok || step.Secondary || isAccessorySlot(lt.OfType) {
+		return false
+	}
+	pos, ok := genetics.NewDNAPosFromString(step.gene())
+	if !ok {
+		return len(lt.Attributes) > 0
+	}
+	g, ok := genetics.GetSchema(0).Gene(pos)
+	return ok && !g.Reserved
+}
+
+// checkRenderStepGene ensures the gene of the render step exists, or that the
+// layer type has a single attribute if the step is not selected by a gene.
+func checkRenderStepGene(lc *Layers, step *RenderStep) error {
+	if _, ok := genetics.NewDNAPosFromString(step.gene()); ok {
+		return nil
+	}
+	if step.Gene != "" {
+		return fmt.Errorf("gene '%s' does not exist", step.Gene)
+	}
+	lt, ok := lc.getLayerType(step.LayerType)
+	if ok && len(lt.Attributes) != 1 {
+		return fmt.Errorf("layer type '%s' has %d attributes but no gene to select them",
+			step.LayerType, len(lt.Attributes))
+	}
+	return nil
+}
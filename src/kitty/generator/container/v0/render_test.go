@@ -0,0 +1,153 @@
+package v0
+
+import (
+	"github.com/kittycash/kittiverse/src/kitty/genetics"
+	"testing"
+)
+
+func TestSetRenderSteps(t *testing.T) {
+	lc, _, _ := compileTestLayers(t, testFiles(map[string]string{
+		"whiskers/default/a_outline.png": "",
+		"markings/default/a_outline.png": "",
+		"markings/default/b_outline.png": "",
+		"noseColor/default/a.png":        "",
+		"nose/default/a_area.png":        "",
+	}))
+	if e := setDominance(lc, genetics.DominanceModel{
+		"bodyColorA": {Mode: genetics.DominanceCodominant},
+	}); e != nil {
+		t.Fatal(e)
+	}
+	cases := []struct {
+		name    string
+		steps   []RenderStep
+		expFail bool
+	}{
+		{"default", nil, false},
+		{"gene of layer type name", []RenderStep{
+			{Canvas: CanvasKitty, LayerType: "eyes"},
+		}, false},
+		{"explicit gene", []RenderStep{
+			{Canvas: CanvasKitty, LayerType: "markings", Gene: "bodyPattern"},
+		}, false},
+		{"no gene with single attribute", []RenderStep{
+			{Canvas: CanvasKitty, LayerType: "whiskers"},
+		}, false},
+		{"no gene with multiple attributes", []RenderStep{
+			{Canvas: CanvasKitty, LayerType: "markings"},
+		}, true},
+		{"unknown gene", []RenderStep{
+			{Canvas: CanvasKitty, LayerType: "eyes", Gene: "eyez"},
+		}, true},
+		{"reserved gene", []RenderStep{
+			{Canvas: CanvasKitty, LayerType: "eyes", Gene: "reservedA"},
+		}, true},
+		{"no canvas", []RenderStep{
+			{LayerType: "eyes"},
+		}, true},
+		{"missing layer type", []RenderStep{
+			{Canvas: CanvasKitty, LayerType: "hat"},
+		}, true},
+		{"optional missing layer type", []RenderStep{
+			{Canvas: CanvasKitty, LayerType: "hat", Gene: "eyes", Optional: true},
+		}, false},
+		{"fill drawn before", []RenderStep{
+			{Canvas: "fur", LayerType: "bodyColorA"},
+			{Canvas: CanvasKitty, LayerType: "body", Fill: "fur"},
+		}, false},
+		{"fill not drawn before", []RenderStep{
+			{Canvas: CanvasKitty, LayerType: "body", Fill: "fur"},
+			{Canvas: "fur", LayerType: "bodyColorA"},
+		}, true},
+		{"fill fallback not drawn", []RenderStep{
+			{Canvas: CanvasKitty, LayerType: "body", FillFallback: "fur"},
+		}, true},
+		{"optional fill with fallback", []RenderStep{
+			{Canvas: "fur", LayerType: "bodyColorA"},
+			{Canvas: "noseColor", LayerType: "noseColor", Optional: true},
+			{Canvas: CanvasKitty, LayerType: "nose", Fill: "noseColor", FillFallback: "fur"},
+		}, false},
+		{"fill of gene not expressed by version 0", []RenderStep{
+			{Canvas: "noseColor", LayerType: "noseColor", Optional: true},
+			{Canvas: CanvasKitty, LayerType: "nose", Fill: "noseColor"},
+		}, true},
+		{"fill fallback of secondary step", []RenderStep{
+			{Canvas: "furA2", LayerType: "bodyColorA", Secondary: true},
+			{Canvas: "noseColor", LayerType: "noseColor", Optional: true},
+			{Canvas: CanvasKitty, LayerType: "nose", Fill: "noseColor", FillFallback: "furA2"},
+		}, true},
+		{"areas without fill", []RenderStep{
+			{Canvas: CanvasKitty, LayerType: "nose"},
+		}, true},
+		{"secondary of codominant gene", []RenderStep{
+			{Canvas: "fur", LayerType: "bodyColorA", Secondary: true},
+		}, false},
+		{"secondary of strict gene", []RenderStep{
+			{Canvas: "fur", LayerType: "bodyColorB", Secondary: true},
+		}, true},
+	}
+	for _, c := range cases {
+		e := setRenderSteps(lc, c.steps)
+		if c.expFail && e == nil {
+			t.Errorf("%s: expected error", c.name)
+		}
+		if !c.expFail && e != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, e)
+		}
+	}
+}
+
+func TestLayers_RenderFile(t *testing.T) {
+	lc, ic, _ := compileTestLayers(t, testFiles(map[string]string{
+		RenderFileName: `[{"canvas": "kitty", "layer_type": "eyes"}]`,
+	}))
+	if len(lc.RenderSteps) != 1 || lc.RenderSteps[0].LayerType != "eyes" {
+		t.Fatalf("expected render steps of the render file, got %+v", lc.RenderSteps)
+	}
+	if _, e := lc.GenerateKitty(ic, genetics.DNA{}); e != nil {
+		t.Error(e)
+	}
+
+	files := testFiles(map[string]string{RenderFileName: `[{"canvas": "kitty", "layer_type": "hat"}]`})
+	if e := NewLayersContainer().Compile(writeTestFiles(t, files), NewImagesContainer()); e == nil {
+		t.Error("expected error of invalid render file")
+	}
+}
+
+func TestDefaultRenderSteps_Accessories(t *testing.T) {
+	lc, _, _ := compileTestLayers(t, testFiles(map[string]string{
+		"accessory_hat/default/a.png": "",
+		"accessory_bow/default/a.png": "",
+	}))
+	var (
+		defaults = DefaultRenderSteps()
+		steps    = lc.RenderSteps
+	)
+	if len(steps) != len(defaults)+2 {
+		t.Fatalf("expected %d render steps, got %d", len(defaults)+2, len(steps))
+	}
+	for i, exp := range []string{"accessory_bow", "accessory_hat"} {
+		step := steps[len(defaults)+i]
+		if step.LayerType != exp || step.gene() != genetics.DNAAccessoryPos.String() {
+			t.Errorf("expected accessory step of '%s', got %+v", exp, step)
+		}
+	}
+}
+
+func TestLayers_GenerateKitty_FillFallback(t *testing.T) {
+	lc, ic, _ := compileTestLayers(t, testFiles(map[string]string{
+		"noseColor/default/a.png": "",
+		"nose/default/a_area.png": "",
+	}))
+	// DNA of version 0 does not express the nose colour, so the nose is
+	// filled with the fur.
+	if _, e := lc.GenerateKitty(ic, genetics.DNA{}); e != nil {
+		t.Fatal(e)
+	}
+
+	// An area without a fill fails rather than panics.
+	nose, _ := lc.getLayerType("nose")
+	if _, e := nose.Layers[0].generateImage(ic, nil, 0, nil); e == nil {
+		t.Error("expected error of area without fill")
+	}
+}
@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/kittycash/kittiverse/src/kitty/generator/container"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Coverage describes which breed and attribute combinations of each layer
+// type are renderable.
+type Coverage struct {
+	LayerTypes []LayerTypeCoverage `json:"layer_types"`
+}
+
+// LayerTypeCoverage is the coverage of a single layer type, in which
+// "Statuses" has a row per breed and a column per attribute.
+type LayerTypeCoverage struct {
+	LayerType  string                    `json:"layer_type"`
+	Breeds     []string                  `json:"breeds"`
+	Attributes []string                  `json:"attributes"`
+	Statuses   [][]container.LayerStatus `json:"statuses"`
+	Own        int                       `json:"own"`
+	Fallback   int                       `json:"fallback"`
+	Missing    int                       `json:"missing"`
+}
+
+// Coverage determines the coverage of all layer types.
+func (i *Instance) Coverage() *Coverage {
+	var (
+		out    = new(Coverage)
+		breeds = i.GetBreeds()
+	)
+	for _, lt := range i.GetLayerTypes() {
+		attributes, _ := i.GetAttributes(lt)
+		ltc := LayerTypeCoverage{
+			LayerType:  lt,
+			Breeds:     breeds,
+			Attributes: attributes,
+			Statuses:   make([][]container.LayerStatus, len(breeds)),
+		}
+		for b, breed := range breeds {
+			ltc.Statuses[b] = make([]container.LayerStatus, len(attributes))
+			for a, attribute := range attributes {
+				status := i.GetLayerStatus(lt, breed, attribute)
+				ltc.Statuses[b][a] = status
+				switch status {
+				case container.LayerOwn:
+					ltc.Own++
+				case container.LayerFallback:
+					ltc.Fallback++
+				default:
+					ltc.Missing++
+				}
+			}
+		}
+		out.LayerTypes = append(out.LayerTypes, ltc)
+	}
+	return out
+}
+
+// WriteTable writes a table per layer type, with a row per breed and a
+// column per attribute.
+func (c *Coverage) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, ltc := range c.LayerTypes {
+		fmt.Fprintf(tw, "%s (own: %d, fallback: %d, missing: %d)\n",
+			ltc.LayerType, ltc.Own, ltc.Fallback, ltc.Missing)
+		fmt.Fprintf(tw, "\t%s\t\n", strings.Join(ltc.Attributes, "\t"))
+		for b, breed := range ltc.Breeds {
+			row := make([]string, len(ltc.Statuses[b]))
+			for a, status := range ltc.Statuses[b] {
+				row[a] = status.String()
+			}
+			fmt.Fprintf(tw, "%s\t%s\t\n", breed, strings.Join(row, "\t"))
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+// WriteCSV writes a record per layer type, breed and attribute combination.
+func (c *Coverage) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"layer_type", "breed", "attribute", "status"})
+	for _, ltc := range c.LayerTypes {
+		for b, breed := range ltc.Breeds {
+			for a, attribute := range ltc.Attributes {
+				cw.Write([]string{ltc.LayerType, breed, attribute, ltc.Statuses[b][a].String()})
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"bytes"
+	"github.com/kittycash/kittiverse/src/kitty/generator/container"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInstance_Coverage(t *testing.T) {
+	i := testInstance(t,
+		"eyes/persian/a_outline.png",
+		"eyes/default/b_outline.png",
+		"eyes/persian/c_outline.png",
+	)
+	var eyes *LayerTypeCoverage
+	cov := i.Coverage()
+	for j := range cov.LayerTypes {
+		if cov.LayerTypes[j].LayerType == "eyes" {
+			eyes = &cov.LayerTypes[j]
+		}
+	}
+	if eyes == nil {
+		t.Fatal("expected coverage of eyes")
+	}
+	exp := LayerTypeCoverage{
+		LayerType:  "eyes",
+		Breeds:     []string{"default", "persian"},
+		Attributes: []string{"a", "b", "c"},
+		Statuses: [][]container.LayerStatus{
+			{container.LayerOwn, container.LayerOwn, container.LayerMissing},
+			{container.LayerOwn, container.LayerFallback, container.LayerOwn},
+		},
+		Own:      4,
+		Fallback: 1,
+		Missing:  1,
+	}
+	if !reflect.DeepEqual(*eyes, exp) {
+		t.Errorf("expected coverage %+v, got %+v", exp, *eyes)
+	}
+
+	var buf bytes.Buffer
+	if e := cov.WriteCSV(&buf); e != nil {
+		t.Fatal(e)
+	}
+	for _, line := range []string{
+		"layer_type,breed,attribute,status\n",
+		"eyes,default,c,missing\n",
+		"eyes,persian,b,fallback\n",
+		"ears,persian,a,fallback\n",
+	} {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("expected CSV line %q", line)
+		}
+	}
+	buf.Reset()
+	if e := cov.WriteTable(&buf); e != nil {
+		t.Fatal(e)
+	}
+	if !strings.Contains(buf.String(), "eyes (own: 4, fallback: 1, missing: 1)") {
+		t.Errorf("expected table of eyes, got:\n%s", buf.String())
+	}
+}
@@ -2,10 +2,13 @@ package generator
 
 import (
 	"github.com/kittycash/kittiverse/src/kitty/generator/container"
+	"github.com/kittycash/kittiverse/src/kitty/generator/container/common"
 	"github.com/kittycash/kittiverse/src/kitty/genetics"
+	"github.com/kittycash/kittiverse/src/kitty/graphics"
 	"github.com/sirupsen/logrus"
 	"github.com/skycoin/skycoin/src/cipher/encoder"
 	"image"
+	"image/gif"
 	"io"
 )
 
@@ -55,6 +58,27 @@ func (i *Instance) Compile(dir string) error {
 	return i.lc.Compile(dir, i.ic)
 }
 
+// CompileWithReport compiles, and reports issues found with the files. The
+// returned error is only of failures that prevent compiling, or of errors
+// found with the files if strict.
+func (i *Instance) CompileWithReport(dir string, strict bool) (*container.Report, error) {
+	report := &container.Report{Strict: strict}
+	if e := i.lc.CompileWithReport(dir, i.ic, report); e != nil {
+		return report, e
+	}
+	return report, nil
+}
+
+// CompileManifest compiles from the files listed in a manifest file, and
+// reports issues found with the files (see CompileWithReport).
+func (i *Instance) CompileManifest(fileName string, strict bool) (*container.Report, error) {
+	report := &container.Report{Strict: strict}
+	if e := i.lc.CompileManifest(fileName, i.ic, report); e != nil {
+		return report, e
+	}
+	return report, nil
+}
+
 func (i *Instance) GetAlleleRanges() *genetics.AlleleRanges {
 	return i.lc.GetAlleleRanges()
 }
@@ -62,3 +86,62 @@ func (i *Instance) GetAlleleRanges() *genetics.AlleleRanges {
 func (i *Instance) GenerateKitty(dna genetics.DNA) (image.Image, error) {
 	return i.lc.GenerateKitty(i.ic, dna)
 }
+
+func (i *Instance) GenerateKittyFrames(dna genetics.DNA) ([]image.Image, error) {
+	return i.lc.GenerateKittyFrames(i.ic, dna)
+}
+
+func (i *Instance) GetBreedMetadata(breed string) (container.Metadata, bool) {
+	return i.lc.GetBreedMetadata(breed)
+}
+
+func (i *Instance) GetAttributeMetadata(layerType, attribute string) (container.Metadata, bool) {
+	return i.lc.GetAttributeMetadata(layerType, attribute)
+}
+
+// Phenotype resolves the breed and attributes expressed by the DNA, the layers
+// used to render them, and their metadata.
+func (i *Instance) Phenotype(dna genetics.DNA) (*container.Phenotype, error) {
+	return i.lc.GetPhenotype(dna)
+}
+
+func (i *Instance) GetLayerTypes() []string {
+	return i.lc.GetLayerTypes()
+}
+
+func (i *Instance) GetBreeds() []string {
+	return i.lc.GetBreeds()
+}
+
+func (i *Instance) GetAttributes(layerType string) ([]string, bool) {
+	return i.lc.GetAttributes(layerType)
+}
+
+func (i *Instance) GetLayerStatus(layerType, breed, attribute string) container.LayerStatus {
+	return i.lc.GetLayerStatus(layerType, breed, attribute)
+}
+
+// AttributeDNA modifies the base DNA to be of the given breed, and to render
+// the given attribute of the layer type.
+func (i *Instance) AttributeDNA(base genetics.DNA, layerType, breed, attribute string) (genetics.DNA, error) {
+	return i.lc.AttributeDNA(base, layerType, breed, attribute)
+}
+
+// GetBackground obtains the named background image.
+func (i *Instance) GetBackground(name string) (image.Image, error) {
+	hash, ok := i.lc.GetBackground(name)
+	if !ok {
+		return nil, common.ErrDoesNotExist
+	}
+	return common.GetImage(i.ic, hash)
+}
+
+// GenerateKittyAnimation generates an animated GIF of the kitty, where each
+// frame is shown for the given delay (in 100ths of a second).
+func (i *Instance) GenerateKittyAnimation(dna genetics.DNA, delay int) (*gif.GIF, error) {
+	frames, e := i.GenerateKittyFrames(dna)
+	if e != nil {
+		return nil, e
+	}
+	return layer.Animate(frames, delay), nil
+}
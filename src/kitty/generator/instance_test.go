@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"bytes"
+	"github.com/kittycash/kittiverse/src/kitty/generator/container/common"
+	"github.com/kittycash/kittiverse/src/kitty/generator/container/v0"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+// testLayerTypes are the layer types of the default render steps.
+var testLayerTypes = []string{
+	"bodyColorA", "bodyColorB", "bodyPattern", "ears", "tail", "body", "nose", "eyesColor", "eyes",
+}
+
+// testInstance compiles an instance of attribute "a" of breed "default" for
+// each layer type, and of the given extra ".png" layer files.
+func testInstance(t *testing.T, extra ...string) *Instance {
+	img := image.NewNRGBA(image.Rect(0, 0, common.XpxLen, common.YpxLen))
+	for y := common.YpxLen / 4; y < common.YpxLen*3/4; y++ {
+		for x := common.XpxLen / 4; x < common.XpxLen*3/4; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if e := png.Encode(&buf, img); e != nil {
+		t.Fatal(e)
+	}
+	dir := t.TempDir()
+	files := extra
+	for _, lt := range testLayerTypes {
+		files = append(files, lt+"/default/a_outline.png")
+	}
+	for _, name := range files {
+		fullPath := path.Join(dir, name)
+		if e := os.MkdirAll(path.Dir(fullPath), 0755); e != nil {
+			t.Fatal(e)
+		}
+		if e := ioutil.WriteFile(fullPath, buf.Bytes(), 0644); e != nil {
+			t.Fatal(e)
+		}
+	}
+	i := NewInstance(v0.NewImagesContainer(), v0.NewLayersContainer())
+	if _, e := i.CompileWithReport(dir, false); e != nil {
+		t.Fatal(e)
+	}
+	return i
+}
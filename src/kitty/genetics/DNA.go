@@ -1,66 +1,95 @@
 package genetics
 
-import "encoding/hex"
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
 
-// DNAPos specifies a position in the kitty DNA.
+// DNAPos identifies a gene of the kitty DNA (see geneTable). The offset of its
+// genotype within DNA is that of the schema of the DNA's version.
 type DNAPos int
 
+// String returns the name of the gene at the position, as of the latest
+// schema (or older schemas, for reserved positions).
 func (p DNAPos) String() string {
-	return string(dnaStringArray[p])
-}
-
-type DNAPosString string
-
-const (
-	DNAVersionPos     DNAPos = iota
-	DNABreedPos       DNAPos = iota*6 - 5
-	DNABodyAttrPos    DNAPos = iota*6 - 5
-	DNABodyColorAPos  DNAPos = iota*6 - 5
-	DNABodyColorBPos  DNAPos = iota*6 - 5
-	DNABodyPatternPos DNAPos = iota*6 - 5
-	DNAEarsAttrPos    DNAPos = iota*6 - 5
-	DNAEyesAttrPos    DNAPos = iota*6 - 5
-	DNAEyesColorPos   DNAPos = iota*6 - 5
-	DNANoseAttrPos    DNAPos = iota*6 - 5
-	DNATailAttrPos    DNAPos = iota*6 - 5
-	DNAReservedAPos   DNAPos = iota*6 - 5
-	DNAReservedBPos   DNAPos = iota*6 - 5
-	DNALen            int    = iota*6 - 5
-)
+	if p == DNAVersionPos {
+		return "version"
+	}
+	for i := len(schemas) - 1; i >= 0; i-- {
+		if g, ok := schemas[i].Gene(p); ok {
+			return g.Name
+		}
+	}
+	return "pos" + strconv.Itoa(int(p))
+}
+
+// NewDNAPosFromString obtains the position of the gene of the given name.
+// Only genes that are expressed in the latest schema (not the version or
+// reserved) are found.
+func NewDNAPosFromString(s string) (DNAPos, bool) {
+	for _, g := range LatestSchema().Expressed() {
+		if g.Name == s {
+			return g.Pos, true
+		}
+	}
+	return 0, false
+}
 
-var dnaStringArray = [...]DNAPosString{
-	DNAVersionPos:     "version",
-	DNABreedPos:       "breed",
-	DNABodyAttrPos:    "body",
-	DNABodyColorAPos:  "bodyColorA",
-	DNABodyColorBPos:  "bodyColorB",
-	DNABodyPatternPos: "bodyPattern",
-	DNAEarsAttrPos:    "ears",
-	DNAEyesAttrPos:    "eyes",
-	DNAEyesColorPos:   "eyesColor",
-	DNANoseAttrPos:    "nose",
-	DNATailAttrPos:    "tail",
-	DNAReservedAPos:   "",
-	DNAReservedBPos:   "",
-}
-
-var dnaPosArray = [...]DNAPos{
-	DNABreedPos,
-	DNABodyAttrPos,
-	DNABodyColorAPos,
-	DNABodyColorBPos,
-	DNABodyPatternPos,
-	DNAEarsAttrPos,
-	DNAEyesAttrPos,
-	DNAEyesColorPos,
-	DNANoseAttrPos,
-	DNATailAttrPos,
+// GenePositions returns the positions of the genes that are expressed in the
+// latest schema, in order.
+func GenePositions() []DNAPos {
+	var out []DNAPos
+	for _, g := range LatestSchema().Expressed() {
+		out = append(out, g.Pos)
+	}
+	return out
 }
 
+// DNAVersion is the version of newly generated kitty DNA.
+const DNAVersion byte = 2
+
+// DNALen is the length of DNA of all versions.
+const DNALen = 73
+
+// DNAVersionPos is the position of the version byte, which is the first byte
+// of DNA of all versions.
+const DNAVersionPos DNAPos = 0
+
+var (
+	DNABreedPos       = genePos("breed")
+	DNABodyAttrPos    = genePos("body")
+	DNABodyColorAPos  = genePos("bodyColorA")
+	DNABodyColorBPos  = genePos("bodyColorB")
+	DNABodyPatternPos = genePos("bodyPattern")
+	DNAEarsAttrPos    = genePos("ears")
+	DNAEyesAttrPos    = genePos("eyes")
+	DNAEyesColorPos   = genePos("eyesColor")
+	DNANoseAttrPos    = genePos("nose")
+	DNATailAttrPos    = genePos("tail")
+	DNANoseColorPos   = genePos("noseColor")
+	DNAAccessoryPos   = genePos("accessory")
+)
+
+var (
+	// Deprecated: reserved A is the nose color since version 1, use
+	// DNANoseColorPos.
+	DNAReservedAPos = DNANoseColorPos
+
+	// Deprecated: reserved B is the accessory since version 2, use
+	// DNAAccessoryPos.
+	DNAReservedBPos = DNAAccessoryPos
+)
+
 // DNA represents a kitty's DNA and contains the genotypes of the kitty.
 // A kittycash genotype is made up of 3 alleles (not 2 like real biology).
 // The right-most allele will always be the dominant allele.
-//		[                (    0)] DNA version (current: 0).
+// The layout of each version is that of its schema (see geneTable), which is
+// currently of alleles of 2 bytes:
+//		[                (    0)] DNA version (current: 2).
 //		[( 1, 2),( 3, 4),( 5, 6)] Breed.
 //		[( 7, 8),( 9,10),(11,12)] Body attribute.
 //		[(13,14),(15,16),(17,18)] Body color A.
@@ -71,8 +100,8 @@ var dnaPosArray = [...]DNAPos{
 //		[(43,44),(45,46),(47,48)] Eyes color.
 //		[(49,50),(51,52),(53,54)] Nose attribute.
 //		[(55,56),(57,58),(59,60)] Tail attribute.
-//		[(61,62),(63,64),(65,66)] (Reserved A).
-//		[(67,68),(69,70),(71,72)] (Reserved B).
+//		[(61,62),(63,64),(65,66)] Nose color (since version 1, previously reserved A).
+//		[(67,68),(69,70),(71,72)] Accessory (since version 2, previously reserved B).
 type DNA [DNALen]byte
 
 func NewDNAFromHex(hs string) (DNA, error) {
@@ -99,67 +128,205 @@ func (d *DNA) Set(b []byte) error {
 	return nil
 }
 
+func (d DNA) Version() byte {
+	return d[DNAVersionPos]
+}
+
+// Schema returns the schema of the DNA's version.
+func (d DNA) Schema() *Schema {
+	return GetSchema(d.Version())
+}
+
+// HasGene determines whether the DNA's version expresses the gene at the
+// given position. Genes introduced in later versions occupy what used to be
+// reserved (random) bytes, and should be ignored for older DNA.
+func (d DNA) HasGene(pos DNAPos) bool {
+	g, ok := d.Schema().Gene(pos)
+	return ok && !g.Reserved
+}
+
 func (d *DNA) SetVersion(v byte) {
 	d[DNAVersionPos] = v
 }
 
+// SetGenotype sets the alleles of the gene at the given position, keeping
+// the low bytes that fit in the gene's allele length. DNA of versions without
+// the gene is unchanged.
 func (d *DNA) SetGenotype(pos DNAPos, a2, a1, a0 Allele) {
-	copy(d[pos+0:pos+2], a2[:])
-	copy(d[pos+2:pos+4], a1[:])
-	copy(d[pos+4:pos+6], a0[:])
+	g, ok := d.Schema().Gene(pos)
+	if !ok {
+		return
+	}
+	for i, a := range [...]Allele{a2, a1, a0} {
+		copy(d[g.Offset+i*g.AlleleLen:], a[AlleleLen-g.AlleleLen:])
+	}
+}
+
+// GetAllele obtains the allele in the slot of the gene at the given position.
+func (d DNA) GetAllele(pos DNAPos, slot AlleleSlot) Allele {
+	return d.GetGenotype(pos).Get(slot)
+}
+
+// SetAllele sets the allele in the slot of the gene at the given position,
+// leaving the other alleles of the gene unchanged.
+func (d *DNA) SetAllele(pos DNAPos, slot AlleleSlot, a Allele) {
+	g := d.GetGenotype(pos)
+	alleles := [...]Allele{g.Get(AlleleRecessive1), g.Get(AlleleRecessive2), g.Get(AlleleDominant)}
+	alleles[slot] = a
+	d.SetGenotype(pos, alleles[AlleleRecessive1], alleles[AlleleRecessive2], alleles[AlleleDominant])
 }
 
 func (d *DNA) SetRandomGenotype(pos DNAPos, ar AlleleRange) {
 	d.SetGenotype(pos, ar.GetRandom(), ar.GetRandom(), ar.GetRandom())
 }
 
+// GetGenotype obtains the genotype of the gene at the given position, or nil
+// if the schema of the DNA's version has no such gene.
 func (d DNA) GetGenotype(pos DNAPos) Genotype {
-	return d[pos : pos+GenotypeLen]
+	g, ok := d.Schema().Gene(pos)
+	if !ok {
+		return nil
+	}
+	return d[g.Offset : g.Offset+g.GenotypeLen()]
+}
+
+func (d DNA) GetPhenotype(pos DNAPos) Allele {
+	return d.GetGenotype(pos).Get(AlleleDominant)
+}
+
+// BreakdownSub contains the version of DNA, and the genotype of each gene of
+// the version's schema (including reserved genes). It is represented in JSON
+// as an object with a "version" field, followed by a field per gene key.
+type BreakdownSub struct {
+	Version string
+	Genes   []GeneBreakdown
+}
+
+type GeneBreakdown struct {
+	Key      string
+	Genotype *GenotypeBreakdown
 }
 
-func (d DNA) GetPhenotype(pos DNAPos) (a Allele) {
-	copy(a[:], d[pos+4:pos+4+AlleleLen])
-	return
+// Get obtains the genotype of the gene of the given key.
+func (b *BreakdownSub) Get(key string) *GenotypeBreakdown {
+	for _, g := range b.Genes {
+		if g.Key == key {
+			return g.Genotype
+		}
+	}
+	return nil
 }
 
-type BreakdownSub struct{
-	Version string `json:"version"`
-	Breed   *GenotypeBreakdown `json:"breed"`
-	BodyAttribute  *GenotypeBreakdown `json:"body_attribute"`
-	BodyColorA     *GenotypeBreakdown `json:"body_color_a"`
-	BodyColorB     *GenotypeBreakdown `json:"body_color_b"`
-	BodyPattern    *GenotypeBreakdown `json:"body_pattern"`
-	EarsAttribute  *GenotypeBreakdown `json:"ears_attribute"`
-	EyesAttribute  *GenotypeBreakdown `json:"eyes_attribute"`
-	EyesColor      *GenotypeBreakdown `json:"eyes_color"`
-	NoseAttribute  *GenotypeBreakdown `json:"nose_attribute"`
-	TailAttribute  *GenotypeBreakdown `json:"tail_attribute"`
-	ReservedA      *GenotypeBreakdown `json:"reserved_a"`
-	ReservedB      *GenotypeBreakdown `json:"reserved_b"`
+// MarshalJSON implements json.Marshaler.
+func (b BreakdownSub) MarshalJSON() ([]byte, error) {
+	fields := []jsonField{{"version", b.Version}}
+	for _, g := range b.Genes {
+		fields = append(fields, jsonField{g.Key, g.Genotype})
+	}
+	return marshalOrdered(fields)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *BreakdownSub) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, e := dec.Token(); e != nil {
+		return e
+	} else if t != json.Delim('{') {
+		return errors.New("breakdown: expected object")
+	}
+	*b = BreakdownSub{}
+	for dec.More() {
+		t, e := dec.Token()
+		if e != nil {
+			return e
+		}
+		key := t.(string)
+		if key == "version" {
+			if e := dec.Decode(&b.Version); e != nil {
+				return e
+			}
+			continue
+		}
+		g := GeneBreakdown{Key: key}
+		if e := dec.Decode(&g.Genotype); e != nil {
+			return e
+		}
+		b.Genes = append(b.Genes, g)
+	}
+	_, e := dec.Token()
+	return e
 }
 
 type DNABreakdown struct {
-	Hex string `json:"hex"`
+	Hex       string       `json:"hex"`
 	Breakdown BreakdownSub `json:"breakdown"`
 }
 
 func (d DNA) Breakdown() *DNABreakdown {
-	return &DNABreakdown{
+	b := &DNABreakdown{
 		Hex: d.Hex(),
 		Breakdown: BreakdownSub{
-			Version: hex.EncodeToString(d.GetGenotype(DNAVersionPos)[:1]),
-			Breed: d.GetGenotype(DNABreedPos).Breakdown(),
-			BodyAttribute: d.GetGenotype(DNABodyAttrPos).Breakdown(),
-			BodyColorA: d.GetGenotype(DNABodyColorAPos).Breakdown(),
-			BodyColorB: d.GetGenotype(DNABodyColorBPos).Breakdown(),
-			BodyPattern: d.GetGenotype(DNABodyPatternPos).Breakdown(),
-			EarsAttribute: d.GetGenotype(DNAEarsAttrPos).Breakdown(),
-			EyesAttribute: d.GetGenotype(DNAEyesAttrPos).Breakdown(),
-			EyesColor: d.GetGenotype(DNAEyesColorPos).Breakdown(),
-			NoseAttribute: d.GetGenotype(DNANoseAttrPos).Breakdown(),
-			TailAttribute: d.GetGenotype(DNATailAttrPos).Breakdown(),
-			ReservedA: d.GetGenotype(DNAReservedAPos).Breakdown(),
-			ReservedB: d.GetGenotype(DNAReservedBPos).Breakdown(),
+			Version: hex.EncodeToString([]byte{d.Version()}),
 		},
 	}
-}
\ No newline at end of file
+	for _, g := range d.Schema().Genes {
+		b.Breakdown.Genes = append(b.Breakdown.Genes, GeneBreakdown{
+			Key:      g.Key,
+			Genotype: d.GetGenotype(g.Pos).Breakdown(),
+		})
+	}
+	return b
+}
+
+// NewDNAFromBreakdown assembles DNA from its breakdown, which must contain
+// every gene of the schema of its version. If the breakdown's hex is not
+// empty, it must agree with the version and genes.
+func NewDNAFromBreakdown(b *DNABreakdown) (DNA, error) {
+	var dna DNA
+	v, e := hex.DecodeString(b.Breakdown.Version)
+	if e != nil || len(v) != 1 {
+		return dna, errors.New("breakdown: invalid version '" + b.Breakdown.Version + "'")
+	}
+	dna.SetVersion(v[0])
+	schema := dna.Schema()
+	for _, gb := range b.Breakdown.Genes {
+		found := false
+		for _, g := range schema.Genes {
+			found = found || g.Key == gb.Key
+		}
+		if !found {
+			return dna, errors.New("breakdown: gene '" + gb.Key + "' does not exist in version " +
+				strconv.Itoa(int(dna.Version())))
+		}
+	}
+	for _, g := range schema.Genes {
+		gb := b.Breakdown.Get(g.Key)
+		if gb == nil {
+			return dna, errors.New("breakdown: gene '" + g.Key + "' is missing")
+		}
+		var alleles [3]Allele
+		for i, hs := range []string{gb.Recessive1, gb.Recessive2, gb.Dominant} {
+			a, e := NewAlleleFromHex(hs)
+			if e != nil {
+				return dna, errors.New("breakdown: gene '" + g.Key +
+					"': invalid allele '" + hs + "'")
+			}
+			alleles[i] = a
+		}
+		dna.SetGenotype(g.Pos, alleles[0], alleles[1], alleles[2])
+	}
+	if b.Hex != "" && !strings.EqualFold(b.Hex, dna.Hex()) {
+		return dna, errors.New("breakdown: hex does not agree with the genes " +
+			"(remove it to use the genes)")
+	}
+	return dna, nil
+}
+
+// NewDNAFromBreakdownJSON assembles DNA from the JSON of its breakdown.
+func NewDNAFromBreakdownJSON(data []byte) (DNA, error) {
+	b := new(DNABreakdown)
+	if e := json.Unmarshal(data, b); e != nil {
+		return DNA{}, e
+	}
+	return NewDNAFromBreakdown(b)
+}
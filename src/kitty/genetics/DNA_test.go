@@ -0,0 +1,75 @@
+package genetics
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDNA_SetAllele(t *testing.T) {
+	var (
+		dna DNA
+		a   = NewAlleleFromUint16(513)
+	)
+	dna.SetVersion(DNAVersion)
+	dna.SetGenotype(DNAEarsAttrPos, NewAlleleFromUint16(1), NewAlleleFromUint16(2), NewAlleleFromUint16(3))
+	for _, slot := range []AlleleSlot{AlleleRecessive1, AlleleRecessive2, AlleleDominant} {
+		d := dna
+		d.SetAllele(DNAEarsAttrPos, slot, a)
+		for _, s := range []AlleleSlot{AlleleRecessive1, AlleleRecessive2, AlleleDominant} {
+			exp := dna.GetAllele(DNAEarsAttrPos, s)
+			if s == slot {
+				exp = a
+			}
+			if got := d.GetAllele(DNAEarsAttrPos, s); got != exp {
+				t.Errorf("set %s: slot %s: expected %s, got %s", slot, s, exp, got)
+			}
+		}
+		if got := d.GetPhenotype(DNAEarsAttrPos); got != d.GetAllele(DNAEarsAttrPos, AlleleDominant) {
+			t.Errorf("set %s: phenotype %s is not the dominant allele", slot, got)
+		}
+		if d.GetGenotype(DNABreedPos).Hex() != dna.GetGenotype(DNABreedPos).Hex() {
+			t.Errorf("set %s: modified another gene", slot)
+		}
+	}
+}
+
+func TestNewDNAFromBreakdown(t *testing.T) {
+	dna := testDNA()
+	data, e := json.Marshal(dna.Breakdown())
+	if e != nil {
+		t.Fatal(e)
+	}
+	out, e := NewDNAFromBreakdownJSON(data)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if out != dna {
+		t.Errorf("expected %s, got %s", dna.Hex(), out.Hex())
+	}
+
+	// Hex is not case sensitive.
+	b := dna.Breakdown()
+	b.Hex = strings.ToUpper(b.Hex)
+	b.Breakdown.Get("ears_attribute").Dominant = strings.ToUpper(b.Breakdown.Get("ears_attribute").Dominant)
+	if out, e = NewDNAFromBreakdown(b); e != nil || out != dna {
+		t.Errorf("upper case: expected %s, got %s (%v)", dna.Hex(), out.Hex(), e)
+	}
+
+	b.Breakdown.Get("ears_attribute").Dominant = "0203"
+	if _, e := NewDNAFromBreakdown(b); e == nil {
+		t.Error("expected error when hex does not agree with genes")
+	}
+	b.Hex = ""
+	if out, e = NewDNAFromBreakdown(b); e != nil {
+		t.Fatal(e)
+	}
+	if a := out.GetPhenotype(DNAEarsAttrPos); a != NewAlleleFromUint16(0x0203) {
+		t.Errorf("expected edited allele 0203, got %s", a)
+	}
+
+	b.Breakdown.Genes = b.Breakdown.Genes[:len(b.Breakdown.Genes)-1]
+	if _, e := NewDNAFromBreakdown(b); e == nil {
+		t.Error("expected error when gene is missing")
+	}
+}
@@ -2,7 +2,6 @@ package genetics
 
 import (
 	"encoding/hex"
-	"encoding/json"
 	"errors"
 	"math/rand"
 	"time"
@@ -37,6 +36,10 @@ func (a Allele) Uint16() uint16 {
 	return uint16(256*int(a[0]) + int(a[1]))
 }
 
+func (a *Allele) FromUint16(n uint16) {
+	*a = NewAlleleFromUint16(n)
+}
+
 func (a Allele) Hex() string {
 	return hex.EncodeToString(a[:])
 }
@@ -67,9 +70,13 @@ func (a Allele) Decrement() Allele {
 	return a
 }
 
+// AlleleRange is the range of alleles of a gene. If values are listed, only
+// the listed alleles (within the range) are valid, and random alleles are
+// selected out of them.
 type AlleleRange struct {
-	Min string `json:"min"`
-	Max string `json:"max"`
+	Min    string   `json:"min"`
+	Max    string   `json:"max"`
+	Values []string `json:"values,omitempty"`
 }
 
 func (r AlleleRange) GetRange() (uint16, uint16) {
@@ -79,49 +86,16 @@ func (r AlleleRange) GetRange() (uint16, uint16) {
 }
 
 func (r AlleleRange) GetRandom() Allele {
-	min, max := r.GetRange()
-	return NewAlleleFromUint16(
-		min + uint16(gen.Intn(int(max-min)+1)),
-	)
-}
-
-type AlleleRanges struct {
-	Breed         AlleleRange `json:"breed"`
-	BodyAttribute AlleleRange `json:"body_attribute"`
-	BodyColorA    AlleleRange `json:"body_color_a"`
-	BodyColorB    AlleleRange `json:"body_color_b"`
-	BodyPattern   AlleleRange `json:"body_pattern"`
-	EarsAttribute AlleleRange `json:"ears_attribute"`
-	EyesAttribute AlleleRange `json:"eyes_attribute"`
-	EyesColor     AlleleRange `json:"eyes_color"`
-	NoseAttribute AlleleRange `json:"nose_attribute"`
-	TailAttribute AlleleRange `json:"tail_attribute"`
+	return r.getRandom(gen)
 }
 
-func (r *AlleleRanges) String(pretty bool) string {
-	if pretty {
-		data, _ := json.MarshalIndent(r, "", "  ")
-		return string(data)
-	} else {
-		data, _ := json.Marshal(r)
-		return string(data)
+func (r AlleleRange) getRandom(rnd Random) Allele {
+	if len(r.Values) > 0 {
+		a, _ := NewAlleleFromHex(r.Values[rnd.Intn(len(r.Values))])
+		return a
 	}
-}
-
-func (r *AlleleRanges) RandomDNA() DNA {
-	var dna DNA
-	dna.SetVersion(0)
-	dna.SetRandomGenotype(DNABreedPos, r.Breed)
-	dna.SetRandomGenotype(DNABodyAttrPos, r.BodyAttribute)
-	dna.SetRandomGenotype(DNABodyColorAPos, r.BodyColorA)
-	dna.SetRandomGenotype(DNABodyColorBPos, r.BodyColorB)
-	dna.SetRandomGenotype(DNABodyPatternPos, r.BodyPattern)
-	dna.SetRandomGenotype(DNAEarsAttrPos, r.EarsAttribute)
-	dna.SetRandomGenotype(DNAEyesAttrPos, r.EyesAttribute)
-	dna.SetRandomGenotype(DNAEyesColorPos, r.EyesColor)
-	dna.SetRandomGenotype(DNANoseAttrPos, r.NoseAttribute)
-	dna.SetRandomGenotype(DNATailAttrPos, r.TailAttribute)
-	dna.SetRandomGenotype(DNAReservedAPos, AlleleRange{Min: "0000", Max: "ffff"})
-	dna.SetRandomGenotype(DNAReservedBPos, AlleleRange{Min: "0000", Max: "ffff"})
-	return dna
+	min, max := r.GetRange()
+	return NewAlleleFromUint16(
+		min + uint16(rnd.Intn(int(max-min)+1)),
+	)
 }
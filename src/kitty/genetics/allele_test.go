@@ -21,7 +21,7 @@ func TestGenome_FromUint16(t *testing.T) {
 		if g.FromUint16(c.in); g != c.exp {
 			t.Error(tPrint(i, c.in, c.exp, g))
 		} else {
-			t.Logf(tPrint(i, c.in, c.exp, g))
+			t.Log(tPrint(i, c.in, c.exp, g))
 		}
 	}
 }
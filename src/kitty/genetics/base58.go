@@ -0,0 +1,73 @@
+package genetics
+
+import (
+	"bytes"
+	"errors"
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/base58"
+	"strings"
+)
+
+const (
+	// DNAPrefix prefixes the base58 representation of DNA. It contains
+	// characters that are not hex, so both representations can be accepted.
+	DNAPrefix = "kc"
+
+	// DNAChecksumLen is the length of the checksum appended to DNA before
+	// base58 encoding.
+	DNAChecksumLen = 4
+
+	base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+)
+
+var (
+	ErrInvalidBase58   = errors.New("invalid base58 DNA")
+	ErrInvalidChecksum = errors.New("invalid DNA checksum")
+)
+
+// NewDNAFromBase58 parses the base58 representation of DNA (see Base58).
+func NewDNAFromBase58(s string) (DNA, error) {
+	var dna DNA
+	if !strings.HasPrefix(s, DNAPrefix) {
+		return dna, ErrInvalidBase58
+	}
+	s = s[len(DNAPrefix):]
+	for _, c := range s {
+		if !strings.ContainsRune(base58Alphabet, c) {
+			return dna, ErrInvalidBase58
+		}
+	}
+	b, e := base58.Base582Hex(s)
+	if e != nil || len(b) != DNALen+DNAChecksumLen {
+		return dna, ErrInvalidBase58
+	}
+	if sum := dnaChecksum(b[:DNALen]); !bytes.Equal(sum, b[DNALen:]) {
+		return dna, ErrInvalidChecksum
+	}
+	return dna, dna.Set(b[:DNALen])
+}
+
+// ParseDNA parses either the hex or the base58 representation of DNA.
+func ParseDNA(s string) (DNA, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, DNAPrefix) {
+		return NewDNAFromBase58(s)
+	}
+	return NewDNAFromHex(s)
+}
+
+// Base58 returns the prefixed base58 representation of the DNA, with a
+// checksum (the first bytes of its SHA256 hash) so that typing mistakes are
+// detected.
+func (d DNA) Base58() string {
+	return DNAPrefix + base58.Hex2Base58String(append(d[:], dnaChecksum(d[:])...))
+}
+
+/*
+	<<< HELPERS >>>
+*/
+
+func dnaChecksum(b []byte) []byte {
+	sum := cipher.SumSHA256(b)
+	return sum[:DNAChecksumLen]
+}
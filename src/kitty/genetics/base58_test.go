@@ -0,0 +1,36 @@
+package genetics
+
+import "testing"
+
+func TestDNA_Base58(t *testing.T) {
+	for _, dna := range []DNA{{}, testDNA()} {
+		s := dna.Base58()
+		out, e := ParseDNA(s)
+		if e != nil {
+			t.Fatalf("parse %s: %v", s, e)
+		}
+		if out != dna {
+			t.Errorf("expected %s, got %s", dna.Hex(), out.Hex())
+		}
+		if out, e = ParseDNA(dna.Hex()); e != nil || out != dna {
+			t.Errorf("parse hex %s: got %s (%v)", dna.Hex(), out.Hex(), e)
+		}
+
+		// Mistype a character.
+		b := []byte(s)
+		if b[10] == 'x' {
+			b[10] = 'y'
+		} else {
+			b[10] = 'x'
+		}
+		if _, e := ParseDNA(string(b)); e != ErrInvalidChecksum && e != ErrInvalidBase58 {
+			t.Errorf("mistyped %s: expected checksum error, got %v", b, e)
+		}
+		if _, e := ParseDNA(s[:len(s)-1]); e == nil {
+			t.Errorf("truncated %s: expected error", s)
+		}
+		if _, e := ParseDNA(s + "0"); e != ErrInvalidBase58 {
+			t.Errorf("invalid character: expected %v, got %v", ErrInvalidBase58, e)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package genetics
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Random is a source of randomness for breeding (implemented by *rand.Rand).
+type Random interface {
+	Intn(n int) int
+	Float64() float64
+}
+
+// Mutation configures how the alleles of a gene mutate when breeding. A
+// mutated allele either shifts to a neighbouring value within the gene's mint
+// range, or (rarely) becomes a novel allele of a separate range. The novel
+// range must be within the gene's allele range, so that novel alleles can be
+// rendered (i.e. it selects rare attributes), and is excluded from the mint
+// range (see MutationModel.MintRanges), so novel alleles only arise by
+// mutation.
+type Mutation struct {
+	Probability      float64      `json:"probability"`                 // of each allele mutating.
+	Step             int          `json:"step,omitempty"`              // maximum shift (default 1).
+	NovelProbability float64      `json:"novel_probability,omitempty"` // of a mutation being novel.
+	Novel            *AlleleRange `json:"novel,omitempty"`             // range of novel alleles.
+}
+
+// Check checks that the mutation is valid.
+func (m Mutation) Check() error {
+	if m.Probability < 0 || m.Probability > 1 {
+		return errors.New("mutation probability must be within [0, 1]")
+	}
+	if m.NovelProbability < 0 || m.NovelProbability > 1 {
+		return errors.New("novel mutation probability must be within [0, 1]")
+	}
+	if m.Step < 0 {
+		return errors.New("mutation step must not be negative")
+	}
+	if m.NovelProbability > 0 && m.Novel == nil {
+		return errors.New("novel mutations have no range")
+	}
+	if m.Novel != nil {
+		if _, e := NewAlleleFromHex(m.Novel.Min); e != nil {
+			return errors.New("invalid novel range minimum '" + m.Novel.Min + "'")
+		}
+		if _, e := NewAlleleFromHex(m.Novel.Max); e != nil {
+			return errors.New("invalid novel range maximum '" + m.Novel.Max + "'")
+		}
+		if min, max := m.Novel.GetRange(); min > max {
+			return errors.New("novel range minimum exceeds maximum")
+		}
+	}
+	return nil
+}
+
+// MutationModel configures the mutation of genes, where "Genes" is keyed by
+// gene name (as of DNAPos.String) and genes not listed mutate as "Default".
+type MutationModel struct {
+	Default Mutation            `json:"default"`
+	Genes   map[string]Mutation `json:"genes,omitempty"`
+}
+
+// Check checks that the mutations are valid and of existing genes.
+func (m *MutationModel) Check() error {
+	if e := m.Default.Check(); e != nil {
+		return fmt.Errorf("default: %v", e)
+	}
+	for name, mut := range m.Genes {
+		if _, ok := NewDNAPosFromString(name); !ok {
+			return fmt.Errorf("gene '%s' does not exist", name)
+		}
+		if e := mut.Check(); e != nil {
+			return fmt.Errorf("gene '%s': %v", name, e)
+		}
+	}
+	return nil
+}
+
+// CheckRanges checks that the novel ranges of the mutations are within the
+// allele ranges of their genes, and leave alleles to mint.
+func (m *MutationModel) CheckRanges(r *AlleleRanges) error {
+	for _, g := range r.Schema().Expressed() {
+		mut := m.Get(g.Pos)
+		if mut.Novel == nil {
+			continue
+		}
+		var (
+			ar, _      = r.Get(g.Pos)
+			min, max   = ar.GetRange()
+			nMin, nMax = mut.Novel.GetRange()
+		)
+		if nMin < min || nMax > max {
+			return fmt.Errorf("gene '%s': novel range [%s, %s] is outside allele range [%s, %s]",
+				g.Name, mut.Novel.Min, mut.Novel.Max, ar.Min, ar.Max)
+		}
+		if len(mintValues(ar, mut.Novel)) == 0 {
+			return fmt.Errorf("gene '%s': novel range [%s, %s] leaves no alleles to mint",
+				g.Name, mut.Novel.Min, mut.Novel.Max)
+		}
+	}
+	return nil
+}
+
+// MintRanges returns the allele ranges excluding the novel range of each
+// gene's mutation, from which new kitties are minted (see
+// AlleleRanges.RandomDNA). Novel alleles within a range are excluded by
+// listing the remaining values (see AlleleRange).
+func (m *MutationModel) MintRanges(r *AlleleRanges) (*AlleleRanges, error) {
+	if e := m.Check(); e != nil {
+		return nil, e
+	}
+	if e := m.CheckRanges(r); e != nil {
+		return nil, e
+	}
+	out := r.Copy()
+	for _, g := range r.Schema().Expressed() {
+		mut := m.Get(g.Pos)
+		if mut.Novel == nil {
+			continue
+		}
+		var (
+			ar, _  = r.Get(g.Pos)
+			values = mintValues(ar, mut.Novel)
+			mint   = AlleleRange{
+				Min: NewAlleleFromUint16(values[0]).String(),
+				Max: NewAlleleFromUint16(values[len(values)-1]).String(),
+			}
+		)
+		if len(ar.Values) > 0 || int(values[len(values)-1]-values[0])+1 != len(values) {
+			for _, v := range values {
+				mint.Values = append(mint.Values, NewAlleleFromUint16(v).String())
+			}
+		}
+		out.Set(g.Pos, mint)
+	}
+	return out, nil
+}
+
+// Get obtains the mutation of the gene at the position.
+func (m *MutationModel) Get(pos DNAPos) Mutation {
+	if mut, ok := m.Genes[pos.String()]; ok {
+		return mut
+	}
+	return m.Default
+}
+
+// Breeder breeds kitties.
+type Breeder struct {
+	Ranges    *AlleleRanges  // of alleles of each gene (of the latest schema).
+	Mutations *MutationModel // (optional) no mutation if nil.
+	Dominance DominanceModel // (optional) strict dominance if nil.
+	Rand      Random         // (optional) package source if nil.
+}
+
+// Breed produces the DNA of an offspring of the parents, of the ranges'
+// schema (parents of older versions are upgraded first).
+//
+// Each gene of the offspring has an allele of each parent, and a third allele
+// of either parent, each chosen at random from the parent's genotype. The
+// alleles then mutate (see Mutation), and are ordered as of the gene's
+// dominance (see Dominance.Order). Reserved bytes are of either parent.
+func (b *Breeder) Breed(a, c DNA) (DNA, error) {
+	var (
+		rnd    = b.random()
+		schema = b.Ranges.Schema()
+		child  DNA
+	)
+	parents := [2]DNA{a, c}
+	for i, p := range parents {
+		up, e := Upgrade(p, schema.Version)
+		if e != nil {
+			return child, fmt.Errorf("parent %d: %v", i+1, e)
+		}
+		parents[i] = up
+	}
+	var mint *AlleleRanges
+	if b.Mutations != nil {
+		var e error
+		if mint, e = b.Mutations.MintRanges(b.Ranges); e != nil {
+			return child, e
+		}
+	}
+	if e := b.Dominance.Check(); e != nil {
+		return child, e
+	}
+	child.SetVersion(schema.Version)
+	for _, g := range schema.Genes {
+		if g.Reserved {
+			p := parents[rnd.Intn(2)]
+			copy(child[g.Offset:g.Offset+g.GenotypeLen()], p.GetGenotype(g.Pos))
+			continue
+		}
+		alleles := [3]Allele{
+			inherit(rnd, parents[0], g.Pos),
+			inherit(rnd, parents[1], g.Pos),
+			inherit(rnd, parents[rnd.Intn(2)], g.Pos),
+		}
+		// The third allele is not always the dominant one.
+		j := rnd.Intn(3)
+		alleles[j], alleles[2] = alleles[2], alleles[j]
+
+		if b.Mutations != nil {
+			ar, _ := mint.Get(g.Pos)
+			mut := b.Mutations.Get(g.Pos)
+			for i := range alleles {
+				alleles[i] = mutate(rnd, mut, ar, alleles[i])
+			}
+		}
+		alleles = b.Dominance.Get(g.Pos).Order(alleles)
+		child.SetGenotype(g.Pos, alleles[0], alleles[1], alleles[2])
+	}
+	return child, nil
+}
+
+/*
+	<<< HELPERS >>>
+*/
+
+func (b *Breeder) random() Random {
+	if b.Rand == nil {
+		return gen
+	}
+	return b.Rand
+}
+
+// mintValues returns the alleles of the range (or its listed values) that are
+// not within the novel range.
+func mintValues(ar AlleleRange, novel *AlleleRange) []uint16 {
+	var (
+		values     []uint16
+		nMin, nMax = novel.GetRange()
+	)
+	add := func(v uint16) {
+		if v < nMin || v > nMax {
+			values = append(values, v)
+		}
+	}
+	if len(ar.Values) > 0 {
+		for _, v := range ar.Values {
+			a, _ := NewAlleleFromHex(v)
+			add(a.Uint16())
+		}
+		return values
+	}
+	min, max := ar.GetRange()
+	for v := int(min); v <= int(max); v++ {
+		add(uint16(v))
+	}
+	return values
+}
+
+// inherit chooses an allele of the parent's gene at random.
+func inherit(rnd Random, parent DNA, pos DNAPos) Allele {
+	return parent.GetAllele(pos, AlleleSlot(rnd.Intn(3)))
+}
+
+// mutate mutates the allele as configured, shifting within the mint range.
+func mutate(rnd Random, m Mutation, ar AlleleRange, a Allele) Allele {
+	if m.Probability == 0 || rnd.Float64() >= m.Probability {
+		return a
+	}
+	if m.Novel != nil && rnd.Float64() < m.NovelProbability {
+		return m.Novel.getRandom(rnd)
+	}
+	step := m.Step
+	if step == 0 {
+		step = 1
+	}
+	// Listed values neighbour each other in order of listing.
+	if len(ar.Values) > 0 {
+		for i, v := range ar.Values {
+			if va, _ := NewAlleleFromHex(v); va == a {
+				next, _ := NewAlleleFromHex(ar.Values[shift(rnd, step, i, 0, len(ar.Values)-1)])
+				return next
+			}
+		}
+		return a
+	}
+	min, max := ar.GetRange()
+	return NewAlleleFromUint16(uint16(shift(rnd, step, int(a.Uint16()), int(min), int(max))))
+}
+
+// shift chooses among the neighbouring values of v (other than itself) within
+// [min, max], returning v if there are none.
+func shift(rnd Random, step, v, min, max int) int {
+	lo, hi := v-step, v+step
+	if lo < min {
+		lo = min
+	}
+	if hi > max {
+		hi = max
+	}
+	n := hi - lo + 1
+	if v >= lo && v <= hi {
+		n--
+	}
+	if n <= 0 {
+		return v
+	}
+	next := lo + rnd.Intn(n)
+	if v >= lo && next >= v {
+		next++
+	}
+	return next
+}
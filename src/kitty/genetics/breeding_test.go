@@ -0,0 +1,196 @@
+package genetics
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func testRanges() *AlleleRanges {
+	r := NewAlleleRanges(LatestSchema())
+	for _, pos := range GenePositions() {
+		r.Set(pos, AlleleRange{Min: "0000", Max: "0009"})
+	}
+	return r
+}
+
+func TestBreeder_Breed(t *testing.T) {
+	r := testRanges()
+	var a, c DNA
+	a.SetVersion(DNAVersion)
+	c.SetVersion(DNAVersion)
+	for _, pos := range GenePositions() {
+		a.SetGenotype(pos, NewAlleleFromUint16(1), NewAlleleFromUint16(2), NewAlleleFromUint16(3))
+		c.SetGenotype(pos, NewAlleleFromUint16(4), NewAlleleFromUint16(5), NewAlleleFromUint16(6))
+	}
+	b := &Breeder{Ranges: r, Rand: rand.New(rand.NewSource(1))}
+	for i := 0; i < 50; i++ {
+		child, e := b.Breed(a, c)
+		if e != nil {
+			t.Fatal(e)
+		}
+		for _, pos := range GenePositions() {
+			var fromA, fromC int
+			for _, slot := range []AlleleSlot{AlleleRecessive1, AlleleRecessive2, AlleleDominant} {
+				switch n := child.GetAllele(pos, slot).Uint16(); {
+				case n >= 1 && n <= 3:
+					fromA++
+				case n >= 4 && n <= 6:
+					fromC++
+				default:
+					t.Fatalf("gene %s: allele %d is of neither parent", pos, n)
+				}
+			}
+			if fromA == 0 || fromC == 0 {
+				t.Fatalf("gene %s: expected alleles of both parents", pos)
+			}
+		}
+	}
+
+	// Breeding is deterministic given the random source.
+	b1 := &Breeder{Ranges: r, Rand: rand.New(rand.NewSource(7))}
+	b2 := &Breeder{Ranges: r, Rand: rand.New(rand.NewSource(7))}
+	c1, _ := b1.Breed(a, c)
+	c2, _ := b2.Breed(a, c)
+	if c1 != c2 {
+		t.Errorf("expected same offspring, got %s and %s", c1.Hex(), c2.Hex())
+	}
+}
+
+func TestBreeder_Mutation(t *testing.T) {
+	r := testRanges()
+	var parent DNA
+	parent.SetVersion(DNAVersion)
+	for _, pos := range GenePositions() {
+		a := NewAlleleFromUint16(9)
+		parent.SetGenotype(pos, a, a, a)
+	}
+	b := &Breeder{
+		Ranges: r,
+		Mutations: &MutationModel{
+			Default: Mutation{Probability: 1, Step: 2},
+			Genes: map[string]Mutation{
+				"accessory": {Probability: 1, NovelProbability: 1,
+					Novel: &AlleleRange{Min: "0002", Max: "0003"}},
+			},
+		},
+		Rand: rand.New(rand.NewSource(3)),
+	}
+	for i := 0; i < 50; i++ {
+		child, e := b.Breed(parent, parent)
+		if e != nil {
+			t.Fatal(e)
+		}
+		for _, pos := range GenePositions() {
+			for _, slot := range []AlleleSlot{AlleleRecessive1, AlleleRecessive2, AlleleDominant} {
+				n := child.GetAllele(pos, slot).Uint16()
+				if pos == DNAAccessoryPos {
+					if n != 2 && n != 3 {
+						t.Fatalf("accessory: expected novel allele, got %d", n)
+					}
+				} else if n != 7 && n != 8 {
+					// Shifted within range, by at most the step.
+					t.Fatalf("gene %s: expected allele 7 or 8, got %d", pos, n)
+				}
+			}
+		}
+	}
+
+	b.Mutations.Genes["accessory"].Novel.Max = "000a"
+	if _, e := b.Breed(parent, parent); e == nil {
+		t.Error("expected error for novel range outside allele range")
+	}
+
+	b.Mutations.Default.Probability = 2
+	if _, e := b.Breed(parent, parent); e == nil {
+		t.Error("expected error for invalid probability")
+	}
+}
+
+func TestMutate_Values(t *testing.T) {
+	var (
+		rnd = rand.New(rand.NewSource(5))
+		m   = Mutation{Probability: 1}
+		ar  = AlleleRange{Min: "0000", Max: "0201", Values: []string{"0000", "0100", "0101", "0201"}}
+	)
+	for i := 0; i < 50; i++ {
+		// Listed values neighbour each other, whatever the distance.
+		if n := mutate(rnd, m, ar, NewAlleleFromUint16(0x0101)).Uint16(); n != 0x0100 && n != 0x0201 {
+			t.Fatalf("expected allele 0100 or 0201, got %04x", n)
+		}
+		if n := ar.getRandom(rnd).Uint16(); n != 0x0000 && n != 0x0100 && n != 0x0101 && n != 0x0201 {
+			t.Fatalf("expected a listed allele, got %04x", n)
+		}
+	}
+}
+
+func TestMutationModel_MintRanges(t *testing.T) {
+	m := &MutationModel{Genes: map[string]Mutation{
+		"eyes": {Novel: &AlleleRange{Min: "0008", Max: "0009"}},
+		"ears": {Novel: &AlleleRange{Min: "0003", Max: "0004"}},
+		"tail": {Novel: &AlleleRange{Min: "0000", Max: "0009"}},
+	}}
+	if _, e := m.MintRanges(testRanges()); e == nil {
+		t.Error("expected error for novel range without alleles to mint")
+	}
+	delete(m.Genes, "tail")
+	mint, e := m.MintRanges(testRanges())
+	if e != nil {
+		t.Fatal(e)
+	}
+	cases := []struct {
+		pos DNAPos
+		exp AlleleRange
+	}{
+		{DNAEyesAttrPos, AlleleRange{Min: "0000", Max: "0007"}},
+		{DNAEarsAttrPos, AlleleRange{Min: "0000", Max: "0009",
+			Values: []string{"0000", "0001", "0002", "0005", "0006", "0007", "0008", "0009"}}},
+		{DNATailAttrPos, AlleleRange{Min: "0000", Max: "0009"}},
+	}
+	for _, c := range cases {
+		if ar, _ := mint.Get(c.pos); !reflect.DeepEqual(ar, c.exp) {
+			t.Errorf("gene %s: expected mint range %+v, got %+v", c.pos, c.exp, ar)
+		}
+	}
+
+	// Minting never produces novel alleles.
+	for i := 0; i < 100; i++ {
+		dna := mint.RandomDNA()
+		for _, slot := range []AlleleSlot{AlleleRecessive1, AlleleRecessive2, AlleleDominant} {
+			if n := dna.GetAllele(DNAEyesAttrPos, slot).Uint16(); n >= 8 {
+				t.Fatalf("eyes: minted novel allele %d", n)
+			}
+			if n := dna.GetAllele(DNAEarsAttrPos, slot).Uint16(); n == 3 || n == 4 {
+				t.Fatalf("ears: minted novel allele %d", n)
+			}
+		}
+	}
+}
+
+func TestBreeder_MutationShiftsWithinMintRange(t *testing.T) {
+	var parent DNA
+	parent.SetVersion(DNAVersion)
+	for _, pos := range GenePositions() {
+		a := NewAlleleFromUint16(7)
+		parent.SetGenotype(pos, a, a, a)
+	}
+	b := &Breeder{
+		Ranges: testRanges(),
+		Mutations: &MutationModel{Default: Mutation{Probability: 1, Step: 2,
+			Novel: &AlleleRange{Min: "0008", Max: "0009"}}},
+		Rand: rand.New(rand.NewSource(3)),
+	}
+	for i := 0; i < 50; i++ {
+		child, e := b.Breed(parent, parent)
+		if e != nil {
+			t.Fatal(e)
+		}
+		for _, pos := range GenePositions() {
+			for _, slot := range []AlleleSlot{AlleleRecessive1, AlleleRecessive2, AlleleDominant} {
+				if n := child.GetAllele(pos, slot).Uint16(); n != 5 && n != 6 {
+					t.Fatalf("gene %s: expected allele 5 or 6, got %d", pos, n)
+				}
+			}
+		}
+	}
+}
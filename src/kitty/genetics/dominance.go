@@ -0,0 +1,161 @@
+package genetics
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// DominanceMode is how the alleles of a genotype determine its phenotype.
+type DominanceMode string
+
+const (
+	DominanceStrict     DominanceMode = "strict"     // the dominant (right-most) allele is expressed.
+	DominanceRanking    DominanceMode = "ranking"    // the highest ranked allele is expressed.
+	DominanceCodominant DominanceMode = "codominant" // the dominant and a recessive allele are both expressed.
+	DominanceIncomplete DominanceMode = "incomplete" // the dominant and a recessive allele are blended (colour genes only).
+)
+
+// Dominance determines the expression of a gene's alleles. In codominance and
+// incomplete dominance, the dominant allele is paired with the right-most
+// recessive allele (r2). The zero value is strict dominance.
+type Dominance struct {
+	Mode    DominanceMode `json:"mode"`
+	Ranking []string      `json:"ranking,omitempty"` // alleles (as hex), highest first.
+}
+
+// Expression is the phenotype of a gene. Codominant genes may express a
+// second allele.
+type Expression struct {
+	Allele       Allele
+	Secondary    Allele
+	HasSecondary bool
+}
+
+// Check checks that the mode is known and the ranking is valid.
+func (d Dominance) Check() error {
+	switch d.Mode {
+	case "", DominanceStrict, DominanceCodominant, DominanceIncomplete:
+		if len(d.Ranking) > 0 {
+			return errors.New("ranking is only used by mode '" + string(DominanceRanking) + "'")
+		}
+	case DominanceRanking:
+		if len(d.Ranking) == 0 {
+			return errors.New("mode '" + string(DominanceRanking) + "' has no ranking")
+		}
+		seen := make(map[Allele]bool)
+		for _, hs := range d.Ranking {
+			a, e := NewAlleleFromHex(hs)
+			if e != nil {
+				return errors.New("invalid allele '" + hs + "' in ranking")
+			}
+			if seen[a] {
+				return errors.New("allele '" + hs + "' is ranked twice")
+			}
+			seen[a] = true
+		}
+	default:
+		return errors.New("unknown dominance mode '" + string(d.Mode) + "'")
+	}
+	return nil
+}
+
+// Express determines the phenotype of the genotype.
+func (d Dominance) Express(g Genotype) Expression {
+	var (
+		r2  = g.Get(AlleleRecessive2)
+		dom = g.Get(AlleleDominant)
+	)
+	switch d.Mode {
+	case DominanceRanking:
+		return Expression{Allele: g.Get(d.highest(g))}
+	case DominanceCodominant:
+		// The right-most recessive allele that differs is expressed too.
+		for _, slot := range []AlleleSlot{AlleleRecessive2, AlleleRecessive1} {
+			if a := g.Get(slot); a != dom {
+				return Expression{Allele: dom, Secondary: a, HasSecondary: true}
+			}
+		}
+		return Expression{Allele: dom}
+	case DominanceIncomplete:
+		blend := (int(dom.Uint16()) + int(r2.Uint16()) + 1) / 2
+		return Expression{Allele: NewAlleleFromUint16(uint16(blend))}
+	default:
+		return Expression{Allele: dom}
+	}
+}
+
+// Order orders inherited alleles (as of SetGenotype) so that the expressed
+// allele is in the dominant slot where the mode allows it. This keeps the
+// strict phenotype (DNA.GetPhenotype) of ranked genes meaningful.
+func (d Dominance) Order(alleles [3]Allele) [3]Allele {
+	if d.Mode != DominanceRanking {
+		return alleles
+	}
+	var g Genotype = make([]byte, 0, GenotypeLen)
+	for _, a := range alleles {
+		g = append(g, a[:]...)
+	}
+	i := d.highest(g)
+	alleles[i], alleles[AlleleDominant] = alleles[AlleleDominant], alleles[i]
+	return alleles
+}
+
+// highest returns the slot of the highest ranked allele. Unranked alleles
+// rank lowest, and ties are won by the right-most allele.
+func (d Dominance) highest(g Genotype) AlleleSlot {
+	rank := func(a Allele) int {
+		for i, hs := range d.Ranking {
+			if r, e := NewAlleleFromHex(hs); e == nil && r == a {
+				return i
+			}
+		}
+		return len(d.Ranking)
+	}
+	best := AlleleDominant
+	for _, slot := range []AlleleSlot{AlleleRecessive2, AlleleRecessive1} {
+		if rank(g.Get(slot)) < rank(g.Get(best)) {
+			best = slot
+		}
+	}
+	return best
+}
+
+// DominanceModel contains the dominance of genes, keyed by gene name (as of
+// DNAPos.String). Genes not listed are of strict dominance.
+type DominanceModel map[string]Dominance
+
+// Check checks that the dominances are valid and of existing genes, and that
+// only colour genes are of incomplete dominance.
+func (m DominanceModel) Check() error {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		pos, ok := NewDNAPosFromString(name)
+		if !ok {
+			return fmt.Errorf("gene '%s' does not exist", name)
+		}
+		if e := m[name].Check(); e != nil {
+			return fmt.Errorf("gene '%s': %v", name, e)
+		}
+		if g, _ := LatestSchema().Gene(pos); m[name].Mode == DominanceIncomplete && !g.Color {
+			return fmt.Errorf("gene '%s': mode '%s' is only of colour genes",
+				name, DominanceIncomplete)
+		}
+	}
+	return nil
+}
+
+// Get obtains the dominance of the gene at the position.
+func (m DominanceModel) Get(pos DNAPos) Dominance {
+	return m[pos.String()]
+}
+
+// Express determines the phenotype of the gene at the position, as of the
+// dominance.
+func (d DNA) Express(pos DNAPos, dom Dominance) Expression {
+	return dom.Express(d.GetGenotype(pos))
+}
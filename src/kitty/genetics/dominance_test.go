@@ -0,0 +1,95 @@
+package genetics
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func genotypeOf(r1, r2, d uint16) Genotype {
+	var dna DNA
+	dna.SetGenotype(DNABreedPos, NewAlleleFromUint16(r1), NewAlleleFromUint16(r2), NewAlleleFromUint16(d))
+	return dna.GetGenotype(DNABreedPos)
+}
+
+func TestDominance_Express(t *testing.T) {
+	ranking := Dominance{Mode: DominanceRanking, Ranking: []string{"0005", "0002"}}
+	cases := []struct {
+		dom          Dominance
+		g            Genotype
+		exp          uint16
+		expSecondary int // -1 if none.
+	}{
+		{Dominance{}, genotypeOf(1, 2, 3), 3, -1},
+		{Dominance{Mode: DominanceStrict}, genotypeOf(1, 2, 3), 3, -1},
+		{ranking, genotypeOf(1, 2, 3), 2, -1},
+		{ranking, genotypeOf(5, 2, 3), 5, -1},
+		{ranking, genotypeOf(1, 4, 3), 3, -1}, // unranked ties go to the dominant allele.
+		{Dominance{Mode: DominanceCodominant}, genotypeOf(1, 2, 3), 3, 2},
+		{Dominance{Mode: DominanceCodominant}, genotypeOf(1, 3, 3), 3, 1},
+		{Dominance{Mode: DominanceCodominant}, genotypeOf(3, 3, 3), 3, -1},
+		{Dominance{Mode: DominanceIncomplete}, genotypeOf(1, 2, 6), 4, -1},
+		{Dominance{Mode: DominanceIncomplete}, genotypeOf(9, 4, 4), 4, -1},
+	}
+	for i, c := range cases {
+		if e := c.dom.Check(); e != nil {
+			t.Fatalf("case %d: %v", i, e)
+		}
+		expr := c.dom.Express(c.g)
+		if n := expr.Allele.Uint16(); n != c.exp {
+			t.Errorf("case %d (%s): expected allele %d, got %d", i, c.dom.Mode, c.exp, n)
+		}
+		switch {
+		case c.expSecondary < 0 && expr.HasSecondary:
+			t.Errorf("case %d (%s): expected no secondary allele, got %s", i, c.dom.Mode, expr.Secondary)
+		case c.expSecondary >= 0 && (!expr.HasSecondary || int(expr.Secondary.Uint16()) != c.expSecondary):
+			t.Errorf("case %d (%s): expected secondary allele %d, got %v", i, c.dom.Mode, c.expSecondary, expr)
+		}
+	}
+}
+
+func TestDominance_Check(t *testing.T) {
+	for i, dom := range []Dominance{
+		{Mode: "unknown"},
+		{Mode: DominanceRanking},
+		{Mode: DominanceRanking, Ranking: []string{"zz"}},
+		{Mode: DominanceRanking, Ranking: []string{"0001", "0001"}},
+		{Mode: DominanceStrict, Ranking: []string{"0001"}},
+	} {
+		if e := dom.Check(); e == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+	if e := (DominanceModel{"unknown": {}}).Check(); e == nil {
+		t.Error("expected error for unknown gene")
+	}
+	if e := (DominanceModel{"eyes": {Mode: DominanceIncomplete}}).Check(); e == nil {
+		t.Error("expected error for incomplete dominance of attribute gene")
+	}
+	if e := (DominanceModel{"eyesColor": {Mode: DominanceIncomplete}}).Check(); e != nil {
+		t.Errorf("unexpected error for incomplete dominance of colour gene: %v", e)
+	}
+}
+
+func TestBreeder_Dominance(t *testing.T) {
+	var a, c DNA
+	a.SetVersion(DNAVersion)
+	c.SetVersion(DNAVersion)
+	a.SetGenotype(DNAEyesAttrPos, NewAlleleFromUint16(1), NewAlleleFromUint16(1), NewAlleleFromUint16(1))
+	c.SetGenotype(DNAEyesAttrPos, NewAlleleFromUint16(2), NewAlleleFromUint16(2), NewAlleleFromUint16(2))
+	b := &Breeder{
+		Ranges:    testRanges(),
+		Dominance: DominanceModel{"eyes": {Mode: DominanceRanking, Ranking: []string{"0002"}}},
+		Rand:      rand.New(rand.NewSource(1)),
+	}
+	for i := 0; i < 20; i++ {
+		child, e := b.Breed(a, c)
+		if e != nil {
+			t.Fatal(e)
+		}
+		// An allele of each parent is inherited, so the ranked allele is
+		// always expressed and ordered into the dominant slot.
+		if n := child.GetPhenotype(DNAEyesAttrPos).Uint16(); n != 2 {
+			t.Fatalf("expected ranked allele 2 in dominant slot, got %d", n)
+		}
+	}
+}
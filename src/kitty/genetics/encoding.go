@@ -0,0 +1,110 @@
+package genetics
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// DNA and Allele are represented as hex in text, JSON and SQL (DNA is also
+// accepted in base58).
+
+// MarshalText implements encoding.TextMarshaler.
+func (d DNA) MarshalText() ([]byte, error) {
+	return []byte(d.Hex()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *DNA) UnmarshalText(text []byte) error {
+	dna, e := ParseDNA(string(text))
+	if e != nil {
+		return e
+	}
+	*d = dna
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d DNA) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Hex())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *DNA) UnmarshalJSON(data []byte) error {
+	var hs string
+	if e := json.Unmarshal(data, &hs); e != nil {
+		return e
+	}
+	return d.UnmarshalText([]byte(hs))
+}
+
+// Value implements driver.Valuer.
+func (d DNA) Value() (driver.Value, error) {
+	return d.Hex(), nil
+}
+
+// Scan implements sql.Scanner. Raw bytes of the DNA's length are accepted as
+// well as hex.
+func (d *DNA) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return d.UnmarshalText([]byte(v))
+	case []byte:
+		if len(v) == DNALen {
+			return d.Set(v)
+		}
+		return d.UnmarshalText(v)
+	default:
+		return fmt.Errorf("cannot scan %T into DNA", src)
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (a Allele) MarshalText() ([]byte, error) {
+	return []byte(a.Hex()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (a *Allele) UnmarshalText(text []byte) error {
+	allele, e := NewAlleleFromHex(string(text))
+	if e != nil {
+		return e
+	}
+	*a = allele
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (a Allele) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.Hex())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (a *Allele) UnmarshalJSON(data []byte) error {
+	var hs string
+	if e := json.Unmarshal(data, &hs); e != nil {
+		return e
+	}
+	return a.UnmarshalText([]byte(hs))
+}
+
+// Value implements driver.Valuer.
+func (a Allele) Value() (driver.Value, error) {
+	return a.Hex(), nil
+}
+
+// Scan implements sql.Scanner. Raw bytes of the allele's length are accepted
+// as well as hex.
+func (a *Allele) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return a.UnmarshalText([]byte(v))
+	case []byte:
+		if len(v) == AlleleLen {
+			return a.Set(v)
+		}
+		return a.UnmarshalText(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Allele", src)
+	}
+}
@@ -0,0 +1,138 @@
+package genetics
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"testing"
+)
+
+var (
+	_ encoding.TextMarshaler   = DNA{}
+	_ encoding.TextUnmarshaler = (*DNA)(nil)
+	_ json.Marshaler           = DNA{}
+	_ json.Unmarshaler         = (*DNA)(nil)
+	_ driver.Valuer            = DNA{}
+	_ sql.Scanner              = (*DNA)(nil)
+	_ encoding.TextMarshaler   = Allele{}
+	_ encoding.TextUnmarshaler = (*Allele)(nil)
+	_ json.Marshaler           = Allele{}
+	_ json.Unmarshaler         = (*Allele)(nil)
+	_ driver.Valuer            = Allele{}
+	_ sql.Scanner              = (*Allele)(nil)
+)
+
+func testDNA() DNA {
+	var dna DNA
+	for i := range dna {
+		dna[i] = byte(i * 7)
+	}
+	dna.SetVersion(DNAVersion)
+	return dna
+}
+
+func TestDNA_JSON(t *testing.T) {
+	type record struct {
+		DNA    DNA         `json:"dna"`
+		Allele Allele      `json:"allele"`
+		Ptr    *DNA        `json:"ptr"`
+		List   []DNA       `json:"list"`
+		Map    map[DNA]int `json:"map"`
+	}
+	dna := testDNA()
+	in := record{
+		DNA:    dna,
+		Allele: NewAlleleFromUint16(513),
+		Ptr:    &dna,
+		List:   []DNA{dna, {}},
+		Map:    map[DNA]int{dna: 1},
+	}
+	data, e := json.Marshal(in)
+	if e != nil {
+		t.Fatal(e)
+	}
+	var raw map[string]interface{}
+	if e := json.Unmarshal(data, &raw); e != nil {
+		t.Fatal(e)
+	}
+	if raw["dna"] != dna.Hex() || raw["allele"] != "0201" {
+		t.Errorf("not encoded as hex: %s", data)
+	}
+	var out record
+	if e := json.Unmarshal(data, &out); e != nil {
+		t.Fatal(e)
+	}
+	if out.DNA != in.DNA || out.Allele != in.Allele || *out.Ptr != dna ||
+		len(out.List) != 2 || out.List[0] != dna || out.Map[dna] != 1 {
+		t.Errorf("round trip: expected %+v, got %+v", in, out)
+	}
+	if e := json.Unmarshal([]byte(`{"dna":"0102"}`), &out); e == nil {
+		t.Error("expected error for DNA of invalid length")
+	}
+	if e := json.Unmarshal([]byte(`{"allele":[1,2]}`), &out); e == nil {
+		t.Error("expected error for allele not encoded as hex")
+	}
+}
+
+func TestDNA_Text(t *testing.T) {
+	dna := testDNA()
+	text, e := dna.MarshalText()
+	if e != nil {
+		t.Fatal(e)
+	}
+	var out DNA
+	if e := out.UnmarshalText(text); e != nil {
+		t.Fatal(e)
+	}
+	if out != dna {
+		t.Errorf("expected %s, got %s", dna.Hex(), out.Hex())
+	}
+
+	a := NewAlleleFromUint16(65535)
+	if text, e = a.MarshalText(); e != nil {
+		t.Fatal(e)
+	}
+	var outA Allele
+	if e := outA.UnmarshalText(text); e != nil {
+		t.Fatal(e)
+	}
+	if outA != a {
+		t.Errorf("expected %s, got %s", a, outA)
+	}
+}
+
+func TestDNA_SQL(t *testing.T) {
+	dna := testDNA()
+	v, e := dna.Value()
+	if e != nil {
+		t.Fatal(e)
+	}
+	for _, src := range []interface{}{v, []byte(v.(string)), dna[:]} {
+		var out DNA
+		if e := out.Scan(src); e != nil {
+			t.Fatalf("scan %T: %v", src, e)
+		}
+		if out != dna {
+			t.Errorf("scan %T: expected %s, got %s", src, dna.Hex(), out.Hex())
+		}
+	}
+	var out DNA
+	if e := out.Scan(nil); e == nil {
+		t.Error("expected error when scanning NULL")
+	}
+
+	a := NewAlleleFromUint16(258)
+	if v, e = a.Value(); e != nil {
+		t.Fatal(e)
+	}
+	for _, src := range []interface{}{v, []byte(v.(string)), a[:]} {
+		var outA Allele
+		if e := outA.Scan(src); e != nil {
+			t.Fatalf("scan %T: %v", src, e)
+		}
+		if outA != a {
+			t.Errorf("scan %T: expected %s, got %s", src, a, outA)
+		}
+	}
+}
@@ -3,9 +3,12 @@ package genetics
 import "encoding/hex"
 
 const (
-	GenotypeLen = 6
+	// GenotypeLen is the length of a genotype of alleles of AlleleLen bytes.
+	GenotypeLen = 3 * AlleleLen
 )
 
+// Genotype contains the three alleles of a gene, each of a third of its
+// length.
 type Genotype []byte
 
 func (g Genotype) Hex() string {
@@ -14,9 +17,9 @@ func (g Genotype) Hex() string {
 
 func (g Genotype) Breakdown() *GenotypeBreakdown {
 	return &GenotypeBreakdown{
-		Recessive1: Allele{g[0], g[1]}.Hex(),
-		Recessive2: Allele{g[2], g[3]}.Hex(),
-		Dominant:   Allele{g[4], g[5]}.Hex(),
+		Recessive1: g.Get(AlleleRecessive1).Hex(),
+		Recessive2: g.Get(AlleleRecessive2).Hex(),
+		Dominant:   g.Get(AlleleDominant).Hex(),
 	}
 }
 
@@ -24,4 +27,41 @@ type GenotypeBreakdown struct {
 	Recessive1 string `json:"r1"`
 	Recessive2 string `json:"r2"`
 	Dominant   string `json:"d"`
-}
\ No newline at end of file
+}
+
+// AlleleSlot specifies an allele of a genotype.
+type AlleleSlot int
+
+const (
+	AlleleRecessive1 AlleleSlot = iota
+	AlleleRecessive2
+	AlleleDominant
+)
+
+var alleleSlotStringArray = [...]string{
+	AlleleRecessive1: "r1",
+	AlleleRecessive2: "r2",
+	AlleleDominant:   "d",
+}
+
+func (s AlleleSlot) String() string {
+	return alleleSlotStringArray[s]
+}
+
+// NewAlleleSlotFromString obtains the allele slot of the given name (as in
+// the genotype breakdown).
+func NewAlleleSlotFromString(s string) (AlleleSlot, bool) {
+	for slot, name := range alleleSlotStringArray {
+		if name == s {
+			return AlleleSlot(slot), true
+		}
+	}
+	return 0, false
+}
+
+// Get obtains the allele in the slot.
+func (g Genotype) Get(slot AlleleSlot) (a Allele) {
+	n := len(g) / 3
+	copy(a[AlleleLen-n:], g[int(slot)*n:int(slot+1)*n])
+	return
+}
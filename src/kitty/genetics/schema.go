@@ -0,0 +1,341 @@
+package genetics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Gene describes a gene of a schema. The position identifies the gene in all
+// versions, while the offset of its genotype within DNA is laid out by the
+// schema. Every gene has three alleles of its allele length (at most
+// AlleleLen bytes), which hold the low bytes of the alleles.
+type Gene struct {
+	Name      string `json:"name"` // as of DNAPos.String().
+	Key       string `json:"key"`  // of breakdowns and allele ranges.
+	Pos       DNAPos `json:"pos"`
+	Offset    int    `json:"offset"`
+	AlleleLen int    `json:"allele_len"`
+	Reserved  bool   `json:"reserved,omitempty"` // random bytes, not expressed.
+	Color     bool   `json:"color,omitempty"`    // alleles index ordered colours (may be blended).
+}
+
+// GenotypeLen returns the length of the gene's genotype within DNA.
+func (g Gene) GenotypeLen() int {
+	return 3 * g.AlleleLen
+}
+
+// MaxAllele returns the largest allele that fits in the gene's allele length.
+func (g Gene) MaxAllele() uint16 {
+	return uint16(int(1)<<(8*uint(g.AlleleLen)) - 1)
+}
+
+// Schema is the layout of the genes of DNA of a version (and later versions,
+// until the next schema). DNA of all versions is of DNALen bytes, of which the
+// genotypes follow the version byte in order of the schema's genes.
+type Schema struct {
+	Version byte   `json:"version"`
+	Genes   []Gene `json:"genes"`
+}
+
+// Gene obtains the gene at the position.
+func (s *Schema) Gene(pos DNAPos) (Gene, bool) {
+	for _, g := range s.Genes {
+		if g.Pos == pos {
+			return g, true
+		}
+	}
+	return Gene{}, false
+}
+
+// Expressed returns the genes that are not reserved, in order.
+func (s *Schema) Expressed() []Gene {
+	var out []Gene
+	for _, g := range s.Genes {
+		if !g.Reserved {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// geneDef defines a gene of geneTable.
+type geneDef struct {
+	Gene
+	Since        byte   // the version that expresses the gene.
+	ReservedName string // of the gene in earlier versions.
+	ReservedKey  string
+}
+
+// geneTable defines the genes of all versions, in order of their genotypes.
+// The position of a gene is its index in the table (following DNAVersionPos),
+// and its alleles are of AlleleLen bytes unless specified. Genes expressed by
+// later versions are reserved (random bytes) in earlier versions, so new genes
+// are appended to the table.
+var geneTable = []geneDef{
+	{Gene: Gene{Name: "breed", Key: "breed"}},
+	{Gene: Gene{Name: "body", Key: "body_attribute"}},
+	{Gene: Gene{Name: "bodyColorA", Key: "body_color_a", Color: true}},
+	{Gene: Gene{Name: "bodyColorB", Key: "body_color_b", Color: true}},
+	{Gene: Gene{Name: "bodyPattern", Key: "body_pattern"}},
+	{Gene: Gene{Name: "ears", Key: "ears_attribute"}},
+	{Gene: Gene{Name: "eyes", Key: "eyes_attribute"}},
+	{Gene: Gene{Name: "eyesColor", Key: "eyes_color", Color: true}},
+	{Gene: Gene{Name: "nose", Key: "nose_attribute"}},
+	{Gene: Gene{Name: "tail", Key: "tail_attribute"}},
+	{Gene: Gene{Name: "noseColor", Key: "nose_color", Color: true}, Since: 1,
+		ReservedName: "reservedA", ReservedKey: "reserved_a"},
+	{Gene: Gene{Name: "accessory", Key: "accessory"}, Since: 2,
+		ReservedName: "reservedB", ReservedKey: "reserved_b"},
+}
+
+// schemas contains the schema of each DNA version that changed the layout (by
+// expressing genes of geneTable), in ascending order of version.
+var schemas = newSchemas(geneTable)
+
+func init() {
+	if e := checkSchemas(schemas); e != nil {
+		panic(e)
+	}
+}
+
+// GetSchema obtains the schema of DNA of the given version. Versions newer
+// than the latest schema use the latest schema.
+func GetSchema(version byte) *Schema {
+	s := schemas[0]
+	for _, next := range schemas[1:] {
+		if next.Version > version {
+			break
+		}
+		s = next
+	}
+	return s
+}
+
+// LatestSchema returns the schema of newly generated DNA (of DNAVersion).
+func LatestSchema() *Schema {
+	return GetSchema(DNAVersion)
+}
+
+// AlleleRanges contains the range of alleles of each expressed gene of a
+// schema. It is represented in JSON as an object with a field per gene key.
+type AlleleRanges struct {
+	schema *Schema
+	ranges []AlleleRange // of schema.Expressed().
+}
+
+// NewAlleleRanges creates allele ranges of the schema's genes, in which every
+// range contains only the zero allele.
+func NewAlleleRanges(s *Schema) *AlleleRanges {
+	r := &AlleleRanges{
+		schema: s,
+		ranges: make([]AlleleRange, len(s.Expressed())),
+	}
+	for i := range r.ranges {
+		r.ranges[i] = AlleleRange{Min: Allele{}.String(), Max: Allele{}.String()}
+	}
+	return r
+}
+
+// Schema returns the schema of the allele ranges.
+func (r *AlleleRanges) Schema() *Schema {
+	return r.schema
+}
+
+// Copy returns a copy of the allele ranges.
+func (r *AlleleRanges) Copy() *AlleleRanges {
+	return &AlleleRanges{
+		schema: r.schema,
+		ranges: append([]AlleleRange(nil), r.ranges...),
+	}
+}
+
+// Get obtains the range of the gene at the position.
+func (r *AlleleRanges) Get(pos DNAPos) (AlleleRange, bool) {
+	for i, g := range r.schema.Expressed() {
+		if g.Pos == pos {
+			return r.ranges[i], true
+		}
+	}
+	return AlleleRange{}, false
+}
+
+// Set sets the range of the gene at the position, and returns false if the
+// schema has no such (expressed) gene.
+func (r *AlleleRanges) Set(pos DNAPos, ar AlleleRange) bool {
+	for i, g := range r.schema.Expressed() {
+		if g.Pos == pos {
+			r.ranges[i] = ar
+			return true
+		}
+	}
+	return false
+}
+
+// Check ensures that the alleles of every range fit in its gene's allele
+// length.
+func (r *AlleleRanges) Check() error {
+	for i, g := range r.schema.Expressed() {
+		if _, max := r.ranges[i].GetRange(); max > g.MaxAllele() {
+			return fmt.Errorf("genetics: alleles of gene '%s' exceed the maximum of %04x",
+				g.Name, g.MaxAllele())
+		}
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (r *AlleleRanges) MarshalJSON() ([]byte, error) {
+	var fields []jsonField
+	for i, g := range r.schema.Expressed() {
+		fields = append(fields, jsonField{g.Key, r.ranges[i]})
+	}
+	return marshalOrdered(fields)
+}
+
+func (r *AlleleRanges) String(pretty bool) string {
+	if pretty {
+		data, _ := json.MarshalIndent(r, "", "  ")
+		return string(data)
+	} else {
+		data, _ := json.Marshal(r)
+		return string(data)
+	}
+}
+
+// RandomDNA generates DNA of the schema's version, with alleles within the
+// ranges (and random reserved bytes).
+func (r *AlleleRanges) RandomDNA() DNA {
+	var dna DNA
+	dna.SetVersion(r.schema.Version)
+	for _, g := range r.schema.Genes {
+		if g.Reserved {
+			gen.Read(dna[g.Offset : g.Offset+g.GenotypeLen()])
+			continue
+		}
+		ar, _ := r.Get(g.Pos)
+		dna.SetRandomGenotype(g.Pos, ar)
+	}
+	return dna
+}
+
+/*
+	<<< HELPERS >>>
+*/
+
+// newSchemas returns the schemas of the versions that express the genes of
+// the table, in ascending order of version.
+func newSchemas(table []geneDef) []*Schema {
+	versions := map[byte]bool{0: true}
+	for _, d := range table {
+		versions[d.Since] = true
+	}
+	var out []*Schema
+	for v := 0; v <= 255; v++ {
+		if versions[byte(v)] {
+			out = append(out, newSchema(byte(v), table))
+		}
+	}
+	return out
+}
+
+// newSchema lays out the genes of the table as of the version.
+func newSchema(version byte, table []geneDef) *Schema {
+	var (
+		s      = &Schema{Version: version}
+		offset = int(DNAVersionPos) + 1
+	)
+	for i, d := range table {
+		g := d.Gene
+		g.Pos = DNAPos(i + 1)
+		g.Offset = offset
+		if g.AlleleLen == 0 {
+			g.AlleleLen = AlleleLen
+		}
+		if version < d.Since {
+			g.Name, g.Key = d.ReservedName, d.ReservedKey
+			g.Reserved, g.Color = true, false
+		}
+		s.Genes = append(s.Genes, g)
+		offset += g.GenotypeLen()
+	}
+	return s
+}
+
+// genePos obtains the position of the gene of geneTable of the given name.
+func genePos(name string) DNAPos {
+	for i, d := range geneTable {
+		if d.Name == name {
+			return DNAPos(i + 1)
+		}
+	}
+	panic("genetics: no gene '" + name + "'")
+}
+
+func checkSchemas(ss []*Schema) error {
+	if len(ss) == 0 || ss[0].Version != 0 {
+		return errors.New("genetics: no schema of version 0")
+	}
+	for i, s := range ss {
+		if i > 0 && s.Version <= ss[i-1].Version {
+			return fmt.Errorf("genetics: schema of version %d is out of order", s.Version)
+		}
+		var (
+			used      = make([]bool, DNALen)
+			names     = make(map[string]bool)
+			positions = make(map[DNAPos]bool)
+		)
+		used[DNAVersionPos] = true
+		for _, g := range s.Genes {
+			prefix := "genetics: schema of version " + strconv.Itoa(int(s.Version)) +
+				": gene '" + g.Name + "'"
+			if g.Name == "" || g.Key == "" || names[g.Name] || names[g.Key] {
+				return errors.New(prefix + ": name or key is empty or duplicate")
+			}
+			names[g.Name], names[g.Key] = true, true
+			if g.Pos <= DNAVersionPos || positions[g.Pos] {
+				return errors.New(prefix + ": position is invalid or duplicate")
+			}
+			positions[g.Pos] = true
+			if g.AlleleLen < 1 || g.AlleleLen > AlleleLen {
+				return fmt.Errorf("%s: allele length must be 1 to %d bytes", prefix, AlleleLen)
+			}
+			if g.Offset < 0 || g.Offset+g.GenotypeLen() > DNALen {
+				return errors.New(prefix + ": exceeds DNA length")
+			}
+			for j := g.Offset; j < g.Offset+g.GenotypeLen(); j++ {
+				if used[j] {
+					return errors.New(prefix + ": overlaps another gene")
+				}
+				used[j] = true
+			}
+		}
+	}
+	return nil
+}
+
+type jsonField struct {
+	key   string
+	value interface{}
+}
+
+// marshalOrdered marshals the fields as a JSON object, in order.
+func marshalOrdered(fields []jsonField) ([]byte, error) {
+	out := []byte{'{'}
+	for i, f := range fields {
+		if i > 0 {
+			out = append(out, ',')
+		}
+		k, e := json.Marshal(f.key)
+		if e != nil {
+			return nil, e
+		}
+		v, e := json.Marshal(f.value)
+		if e != nil {
+			return nil, e
+		}
+		out = append(append(append(out, k...), ':'), v...)
+	}
+	return append(out, '}'), nil
+}
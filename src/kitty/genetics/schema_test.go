@@ -0,0 +1,175 @@
+package genetics
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSchema_HasGene(t *testing.T) {
+	cases := []struct {
+		version byte
+		pos     DNAPos
+		exp     bool
+	}{
+		{0, DNATailAttrPos, true},
+		{0, DNANoseColorPos, false},
+		{0, DNAAccessoryPos, false},
+		{1, DNANoseColorPos, true},
+		{1, DNAAccessoryPos, false},
+		{2, DNAAccessoryPos, true},
+		{9, DNAAccessoryPos, true},
+	}
+	for _, c := range cases {
+		var dna DNA
+		dna.SetVersion(c.version)
+		if got := dna.HasGene(c.pos); got != c.exp {
+			t.Errorf("version %d: gene %s: expected %v, got %v", c.version, c.pos, c.exp, got)
+		}
+	}
+}
+
+func TestSchema_Breakdown(t *testing.T) {
+	for _, version := range []byte{0, 1, 2} {
+		dna := testDNA()
+		dna.SetVersion(version)
+		data, e := json.Marshal(dna.Breakdown())
+		if e != nil {
+			t.Fatal(e)
+		}
+		var keys []string
+		for _, g := range GetSchema(version).Genes {
+			keys = append(keys, `"`+g.Key+`":`)
+		}
+		if i := strings.Index(string(data), `"version":`); i < 0 || !inOrder(string(data[i:]), keys) {
+			t.Errorf("version %d: genes not in schema order: %s", version, data)
+		}
+		out, e := NewDNAFromBreakdownJSON(data)
+		if e != nil {
+			t.Fatalf("version %d: %v", version, e)
+		}
+		if out != dna {
+			t.Errorf("version %d: expected %s, got %s", version, dna.Hex(), out.Hex())
+		}
+	}
+
+	// Genes of other versions are rejected.
+	dna := testDNA()
+	dna.SetVersion(0)
+	b := dna.Breakdown()
+	b.Breakdown.Genes = append(b.Breakdown.Genes, GeneBreakdown{
+		Key: "accessory", Genotype: dna.GetGenotype(DNAAccessoryPos).Breakdown()})
+	if _, e := NewDNAFromBreakdown(b); e == nil {
+		t.Error("expected error for gene not in schema")
+	}
+}
+
+func TestAlleleRanges_RandomDNA(t *testing.T) {
+	r := NewAlleleRanges(LatestSchema())
+	if !r.Set(DNAEarsAttrPos, AlleleRange{Min: "0002", Max: "0003"}) {
+		t.Fatal("failed to set range")
+	}
+	for i := 0; i < 20; i++ {
+		dna := r.RandomDNA()
+		if dna.Version() != DNAVersion {
+			t.Fatalf("expected version %d, got %d", DNAVersion, dna.Version())
+		}
+		for _, slot := range []AlleleSlot{AlleleRecessive1, AlleleRecessive2, AlleleDominant} {
+			if n := dna.GetAllele(DNAEarsAttrPos, slot).Uint16(); n < 2 || n > 3 {
+				t.Fatalf("allele %d out of range", n)
+			}
+		}
+	}
+}
+
+func TestSchema_Layout(t *testing.T) {
+	// The genes of all versions keep the layout of version 0 DNA.
+	for _, s := range schemas {
+		for i, g := range s.Genes {
+			if g.Pos != DNAPos(i+1) || g.Offset != 1+i*GenotypeLen || g.AlleleLen != AlleleLen {
+				t.Errorf("version %d: gene %s: unexpected position %d, offset %d or allele length %d",
+					s.Version, g.Name, g.Pos, g.Offset, g.AlleleLen)
+			}
+		}
+		if last := s.Genes[len(s.Genes)-1]; last.Offset+last.GenotypeLen() != DNALen {
+			t.Errorf("version %d: genes do not fill DNA", s.Version)
+		}
+	}
+	if DNAReservedAPos != DNANoseColorPos || DNAReservedBPos != DNAAccessoryPos {
+		t.Error("deprecated positions do not agree with their genes")
+	}
+
+	cases := []struct {
+		name  string
+		table []geneDef
+	}{
+		{"allele length", []geneDef{{Gene: Gene{Name: "breed", Key: "breed", AlleleLen: AlleleLen + 1}}}},
+		{"duplicate key", []geneDef{
+			{Gene: Gene{Name: "breed", Key: "breed"}},
+			{Gene: Gene{Name: "body", Key: "breed"}},
+		}},
+		{"exceeds DNA length", make([]geneDef, 13)},
+	}
+	for i := range cases[2].table {
+		cases[2].table[i].Gene = Gene{Name: "gene" + strconv.Itoa(i), Key: "key" + strconv.Itoa(i)}
+	}
+	for _, c := range cases {
+		if e := checkSchemas(newSchemas(c.table)); e == nil {
+			t.Errorf("%s: expected error", c.name)
+		}
+	}
+}
+
+func TestSchema_AlleleLen(t *testing.T) {
+	defer func(ss []*Schema) { schemas = ss }(schemas)
+	schemas = newSchemas([]geneDef{
+		{Gene: Gene{Name: "breed", Key: "breed", AlleleLen: 1}},
+		{Gene: Gene{Name: "body", Key: "body_attribute"}},
+	})
+	if e := checkSchemas(schemas); e != nil {
+		t.Fatal(e)
+	}
+	if g, _ := GetSchema(0).Gene(DNABodyAttrPos); g.Offset != 4 {
+		t.Fatalf("expected body offset 4, got %d", g.Offset)
+	}
+
+	var dna DNA
+	dna.SetGenotype(DNABreedPos,
+		NewAlleleFromUint16(0x0001), NewAlleleFromUint16(0x0102), NewAlleleFromUint16(0x00ff))
+	dna.SetGenotype(DNABodyAttrPos,
+		NewAlleleFromUint16(0x0203), NewAlleleFromUint16(0x0405), NewAlleleFromUint16(0x0607))
+	if exp := "000102ff020304050607"; !strings.HasPrefix(dna.Hex(), exp) {
+		t.Errorf("expected DNA to begin with %s, got %s", exp, dna.Hex())
+	}
+	if a := dna.GetAllele(DNABreedPos, AlleleRecessive2); a.Uint16() != 0x0002 {
+		t.Errorf("expected breed allele 0002, got %s", a)
+	}
+	if a := dna.GetPhenotype(DNABodyAttrPos); a.Uint16() != 0x0607 {
+		t.Errorf("expected body phenotype 0607, got %s", a)
+	}
+	if out, e := NewDNAFromBreakdown(dna.Breakdown()); e != nil || out != dna {
+		t.Errorf("expected breakdown to round trip, got %s (%v)", out.Hex(), e)
+	}
+
+	r := NewAlleleRanges(GetSchema(0))
+	r.Set(DNABreedPos, AlleleRange{Min: "0000", Max: "00ff"})
+	if e := r.Check(); e != nil {
+		t.Error(e)
+	}
+	r.Set(DNABreedPos, AlleleRange{Min: "0000", Max: "0100"})
+	if e := r.Check(); e == nil {
+		t.Error("expected error for alleles exceeding the allele length")
+	}
+}
+
+func inOrder(s string, subs []string) bool {
+	for _, sub := range subs {
+		i := strings.Index(s, sub)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(sub):]
+	}
+	return true
+}
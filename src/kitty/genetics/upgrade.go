@@ -0,0 +1,124 @@
+package genetics
+
+import (
+	"errors"
+	"strconv"
+)
+
+var ErrUnsupportedVersion = errors.New("unsupported DNA version")
+
+// ConvertFunc converts DNA to the layout of a later version.
+type ConvertFunc func(dna DNA) (DNA, error)
+
+type converter struct {
+	to      byte
+	convert ConvertFunc
+}
+
+// converters are keyed by the version they convert from.
+var converters = make(map[byte]converter)
+
+// UpgradeRule obtains the alleles of a gene that becomes expressed when DNA is
+// upgraded, from the DNA of the earlier version.
+type UpgradeRule func(from DNA) (a2, a1, a0 Allele)
+
+// InheritRule returns the upgrade rule by which a gene takes the alleles of
+// the gene at the given position (or the zero allele, if not expressed).
+func InheritRule(pos DNAPos) UpgradeRule {
+	return func(from DNA) (a2, a1, a0 Allele) {
+		if !from.HasGene(pos) {
+			return
+		}
+		g := from.GetGenotype(pos)
+		return g.Get(AlleleRecessive1), g.Get(AlleleRecessive2), g.Get(AlleleDominant)
+	}
+}
+
+// upgradeRules are the rules of genes that became expressed, by which
+// upgraded DNA keeps its look. Genes without a rule are set to the zero
+// allele (i.e. the accessory's allele of no accessory).
+var upgradeRules = map[DNAPos]UpgradeRule{
+	// Noses were filled with the fur before version 1.
+	DNANoseColorPos: InheritRule(DNABodyColorAPos),
+}
+
+func init() {
+	for v := byte(0); v < DNAVersion; v++ {
+		RegisterConverter(v, v+1, SchemaConverter(v+1))
+	}
+}
+
+// CheckVersion returns ErrUnsupportedVersion if DNA of the version is newer
+// than DNAVersion (and so of unknown layout).
+func CheckVersion(version byte) error {
+	if version > DNAVersion {
+		return ErrUnsupportedVersion
+	}
+	return nil
+}
+
+// RegisterConverter registers the conversion of DNA of version "from" to the
+// later version "to", replacing any converter from the same version.
+func RegisterConverter(from, to byte, convert ConvertFunc) {
+	if to <= from {
+		panic("genetics: converter must convert to a later version")
+	}
+	converters[from] = converter{to: to, convert: convert}
+}
+
+// SchemaConverter returns a converter to the given version that lays out the
+// genes as of the version's schema. It keeps the genes expressed in both
+// versions' schemas and the bytes of reserved genes, and sets the genes that
+// become expressed as of their upgrade rules (see upgradeRules).
+func SchemaConverter(to byte) ConvertFunc {
+	return func(dna DNA) (DNA, error) {
+		var out DNA
+		out.SetVersion(to)
+		for _, g := range GetSchema(to).Genes {
+			rule := InheritRule(g.Pos)
+			switch {
+			case g.Reserved:
+				copy(out[g.Offset:g.Offset+g.GenotypeLen()], dna.GetGenotype(g.Pos))
+				continue
+			case !dna.HasGene(g.Pos):
+				if rule = upgradeRules[g.Pos]; rule == nil {
+					continue
+				}
+			}
+			a2, a1, a0 := rule(dna)
+			out.SetGenotype(g.Pos, a2, a1, a0)
+		}
+		return out, nil
+	}
+}
+
+// Upgrade converts the DNA to the given version, by applying the registered
+// converters in turn.
+func Upgrade(dna DNA, to byte) (DNA, error) {
+	if e := CheckVersion(to); e != nil {
+		return dna, e
+	}
+	for dna.Version() < to {
+		c, ok := converters[dna.Version()]
+		if !ok {
+			return dna, errors.New("no converter from DNA version " + strconv.Itoa(int(dna.Version())))
+		}
+		if c.to > to {
+			return dna, errors.New("no conversion from DNA version " + strconv.Itoa(int(dna.Version())) +
+				" to " + strconv.Itoa(int(to)))
+		}
+		from := dna.Version()
+		var e error
+		if dna, e = c.convert(dna); e != nil {
+			return dna, e
+		}
+		if dna.Version() != c.to {
+			return dna, errors.New("converter from DNA version " + strconv.Itoa(int(from)) +
+				" produced version " + strconv.Itoa(int(dna.Version())))
+		}
+	}
+	if dna.Version() > to {
+		return dna, errors.New("cannot downgrade DNA of version " + strconv.Itoa(int(dna.Version())))
+	}
+	return dna, nil
+}
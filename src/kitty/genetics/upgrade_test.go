@@ -0,0 +1,44 @@
+package genetics
+
+import "testing"
+
+func TestUpgrade(t *testing.T) {
+	dna := testDNA()
+	dna.SetVersion(0)
+	out, e := Upgrade(dna, DNAVersion)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if out.Version() != DNAVersion {
+		t.Errorf("expected version %d, got %d", DNAVersion, out.Version())
+	}
+	for _, g := range GetSchema(0).Expressed() {
+		if out.GetGenotype(g.Pos).Hex() != dna.GetGenotype(g.Pos).Hex() {
+			t.Errorf("gene %s was modified", g.Name)
+		}
+	}
+	// The nose colour inherits the fur colour, and there is no accessory.
+	if out.GetGenotype(DNANoseColorPos).Hex() != dna.GetGenotype(DNABodyColorAPos).Hex() {
+		t.Errorf("expected nose color %s, got %s",
+			dna.GetGenotype(DNABodyColorAPos).Hex(), out.GetGenotype(DNANoseColorPos).Hex())
+	}
+	if h := out.GetGenotype(DNAAccessoryPos).Hex(); h != "000000000000" {
+		t.Errorf("expected no accessory, got %s", h)
+	}
+
+	// Genes expressed since version 1 are kept when upgrading from it.
+	dna.SetVersion(1)
+	if out, e = Upgrade(dna, 2); e != nil {
+		t.Fatal(e)
+	}
+	if out.GetGenotype(DNANoseColorPos).Hex() != dna.GetGenotype(DNANoseColorPos).Hex() {
+		t.Error("nose color was modified")
+	}
+
+	if _, e := Upgrade(out, 1); e == nil {
+		t.Error("expected error when downgrading")
+	}
+	if _, e := Upgrade(dna, DNAVersion+1); e != ErrUnsupportedVersion {
+		t.Errorf("expected %v, got %v", ErrUnsupportedVersion, e)
+	}
+}
@@ -0,0 +1,179 @@
+package layer
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+	"sort"
+)
+
+const (
+	// PaletteLen is the maximum number of colors in a GIF palette.
+	PaletteLen = 256
+)
+
+// Animate combines frames into an animated GIF that loops forever, in which
+// each frame is shown for the given delay (in 100ths of a second).
+// The palette is quantised from the colors of all frames, where index 0 of
+// the palette represents transparency.
+func Animate(frames []image.Image, delay int) *gif.GIF {
+	var (
+		palette = append(color.Palette{color.RGBA{}}, Quantise(frames, PaletteLen-1)...)
+		cache   = make(map[uint16]uint8)
+		out     = &gif.GIF{LoopCount: 0}
+	)
+	for _, frame := range frames {
+		b := frame.Bounds()
+		dst := image.NewPaletted(b, palette)
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				c, ok := toOpaqueKey(frame.At(x, y))
+				if !ok {
+					continue // index 0 is transparent.
+				}
+				i, has := cache[c]
+				if !has {
+					i = uint8(palette[1:].Index(fromKey(c)) + 1)
+					cache[c] = i
+				}
+				dst.SetColorIndex(x, y, i)
+			}
+		}
+		out.Image = append(out.Image, dst)
+		out.Delay = append(out.Delay, delay)
+		out.Disposal = append(out.Disposal, gif.DisposalBackground)
+	}
+	return out
+}
+
+// Quantise reduces the opaque colors of the given images to a palette of at
+// most n colors using the median cut algorithm.
+func Quantise(imgs []image.Image, n int) color.Palette {
+	// Histogram of colors (5 bits per channel).
+	hist := make(map[uint16]int)
+	for _, img := range imgs {
+		b := img.Bounds()
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				if c, ok := toOpaqueKey(img.At(x, y)); ok {
+					hist[c]++
+				}
+			}
+		}
+	}
+	if len(hist) == 0 {
+		return color.Palette{color.Black}
+	}
+	all := make(colorBox, 0, len(hist))
+	for c, count := range hist {
+		all = append(all, colorCount{key: c, count: count})
+	}
+
+	// Repeatedly split the box with the most pixels.
+	boxes := []colorBox{all}
+	for len(boxes) < n {
+		best, bestCount := -1, 0
+		for i, box := range boxes {
+			if len(box) > 1 && box.pixels() > bestCount {
+				best, bestCount = i, box.pixels()
+			}
+		}
+		if best == -1 {
+			break
+		}
+		a, b := boxes[best].split()
+		boxes[best] = a
+		boxes = append(boxes, b)
+	}
+
+	palette := make(color.Palette, len(boxes))
+	for i, box := range boxes {
+		palette[i] = box.average()
+	}
+	return palette
+}
+
+/*
+	<<< HELPERS >>>
+*/
+
+func toOpaqueKey(c color.Color) (uint16, bool) {
+	nc := color.NRGBAModel.Convert(c).(color.NRGBA)
+	if nc.A < 128 {
+		return 0, false
+	}
+	return uint16(nc.R>>3)<<10 | uint16(nc.G>>3)<<5 | uint16(nc.B>>3), true
+}
+
+func channel(key uint16, i uint) uint8 {
+	v := uint8(key>>(10-5*i)) & 0x1f
+	return v<<3 | v>>2
+}
+
+func fromKey(key uint16) color.Color {
+	return color.RGBA{channel(key, 0), channel(key, 1), channel(key, 2), 255}
+}
+
+type colorCount struct {
+	key   uint16
+	count int
+}
+
+type colorBox []colorCount
+
+func (b colorBox) pixels() int {
+	var n int
+	for _, c := range b {
+		n += c.count
+	}
+	return n
+}
+
+// split splits the box along its widest channel at the median pixel.
+func (b colorBox) split() (colorBox, colorBox) {
+	var (
+		widest   uint
+		maxRange uint8
+	)
+	for i := uint(0); i < 3; i++ {
+		min, max := uint8(255), uint8(0)
+		for _, c := range b {
+			v := channel(c.key, i)
+			if v < min {
+				min = v
+			}
+			if v > max {
+				max = v
+			}
+		}
+		if max-min >= maxRange {
+			widest, maxRange = i, max-min
+		}
+	}
+	sort.Slice(b, func(i, j int) bool {
+		return channel(b[i].key, widest) < channel(b[j].key, widest)
+	})
+	var (
+		half = b.pixels() / 2
+		sum  int
+		at   = 1
+	)
+	for i := 0; i < len(b)-1; i++ {
+		if sum += b[i].count; sum >= half {
+			at = i + 1
+			break
+		}
+	}
+	return b[:at], b[at:]
+}
+
+func (b colorBox) average() color.Color {
+	var r, g, bl, n int
+	for _, c := range b {
+		r += int(channel(c.key, 0)) * c.count
+		g += int(channel(c.key, 1)) * c.count
+		bl += int(channel(c.key, 2)) * c.count
+		n += c.count
+	}
+	return color.RGBA{uint8(r / n), uint8(g / n), uint8(bl / n), 255}
+}
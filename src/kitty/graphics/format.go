@@ -0,0 +1,123 @@
+package layer
+
+import (
+	"encoding/hex"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"path"
+	"strings"
+)
+
+// Format is an output image format.
+type Format string
+
+const (
+	FormatPNG  Format = "png"
+	FormatJPEG Format = "jpeg"
+	FormatGIF  Format = "gif"
+	FormatRGBA Format = "rgba" // raw non-premultiplied RGBA bytes, row by row.
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown image format")
+	ErrInvalidColor  = errors.New("invalid color, expected hex of form 'rrggbb' or 'rrggbbaa'")
+)
+
+var formatsByExt = map[string]Format{
+	".png":  FormatPNG,
+	".jpg":  FormatJPEG,
+	".jpeg": FormatJPEG,
+	".gif":  FormatGIF,
+	".rgba": FormatRGBA,
+}
+
+// ParseFormat obtains the format of the given name (i.e. "png", "jpg").
+func ParseFormat(name string) (Format, error) {
+	f, ok := formatsByExt["."+strings.ToLower(name)]
+	if !ok {
+		return "", ErrUnknownFormat
+	}
+	return f, nil
+}
+
+// FormatFromFileName obtains the format from the extension of the file name.
+func FormatFromFileName(fn string) (Format, bool) {
+	f, ok := formatsByExt[strings.ToLower(path.Ext(fn))]
+	return f, ok
+}
+
+// Ext returns the file extension of the format.
+func (f Format) Ext() string {
+	if f == FormatJPEG {
+		return ".jpg"
+	}
+	return "." + string(f)
+}
+
+// EncodeOptions are options for encoding images.
+type EncodeOptions struct {
+	Background color.Color // background of formats without alpha (defaults to white).
+	Quality    int         // JPEG quality 1-100 (defaults to 90).
+}
+
+// Encode writes the image in the given format.
+func Encode(w io.Writer, img image.Image, f Format, opts *EncodeOptions) error {
+	if opts == nil {
+		opts = new(EncodeOptions)
+	}
+	switch f {
+	case FormatPNG:
+		return png.Encode(w, img)
+	case FormatJPEG:
+		bg := opts.Background
+		if bg == nil {
+			bg = color.White
+		}
+		quality := opts.Quality
+		if quality <= 0 {
+			quality = 90
+		}
+		dst := image.NewRGBA(img.Bounds())
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(bg), image.ZP, draw.Src)
+		draw.Draw(dst, dst.Bounds(), img, img.Bounds().Min, draw.Over)
+		return jpeg.Encode(w, dst, &jpeg.Options{Quality: quality})
+	case FormatGIF:
+		return gif.EncodeAll(w, Animate([]image.Image{img}, 0))
+	case FormatRGBA:
+		dst := image.NewNRGBA(img.Bounds())
+		draw.Draw(dst, dst.Bounds(), img, img.Bounds().Min, draw.Src)
+		_, e := w.Write(dst.Pix)
+		return e
+	default:
+		return ErrUnknownFormat
+	}
+}
+
+// Decode reads an image of any of the decodable formats (png, jpeg or gif).
+func Decode(r io.Reader) (image.Image, error) {
+	img, _, e := image.Decode(r)
+	return img, e
+}
+
+// ParseColor parses a color from hex of form "rrggbb" or "rrggbbaa" (with an
+// optional "#" prefix).
+func ParseColor(s string) (color.Color, error) {
+	b, e := hex.DecodeString(strings.TrimPrefix(s, "#"))
+	if e != nil {
+		return nil, ErrInvalidColor
+	}
+	switch len(b) {
+	case 3:
+		return color.NRGBA{R: b[0], G: b[1], B: b[2], A: 255}, nil
+	case 4:
+		return color.NRGBA{R: b[0], G: b[1], B: b[2], A: b[3]}, nil
+	default:
+		return nil, ErrInvalidColor
+	}
+}
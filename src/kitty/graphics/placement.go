@@ -1,10 +1,45 @@
 package layer
 
+import (
+	"encoding/json"
+	"image"
+	"image/color"
+	"math"
+)
+
 type Placement struct {
 	CoordX  uint64  `json:"coord_x"` // x coordinate
 	CoordY  uint64  `json:"coord_y"` // y coordinate
-	ScaleX  float64 `json:"scale_x"` // x scale factor
-	ScaleY  float64 `json:"scale_y"` // y scale factor
+	ScaleX  float64 `json:"scale_x"` // x scale factor (defaults to 1.0)
+	ScaleY  float64 `json:"scale_y"` // y scale factor (defaults to 1.0)
 	Rotate  float64 `json:"rotate"`  // clockwise rotation in radians
-	Opacity float64 `json:"opacity"` // opacity factor 0.0 - 1.0 (TODO: Implement)
+	Opacity float64 `json:"opacity"` // opacity factor 0.0 - 1.0 (defaults to 1.0, 0.0 is unset)
+}
+
+// NewPlacement returns a placement at the origin, with scale factors and
+// opacity of 1.0.
+func NewPlacement() Placement {
+	return Placement{ScaleX: 1, ScaleY: 1, Opacity: 1}
+}
+
+// UnmarshalJSON ensures omitted scale factors and opacity default to 1.0.
+func (p *Placement) UnmarshalJSON(data []byte) error {
+	type placement Placement
+	v := placement(NewPlacement())
+	if e := json.Unmarshal(data, &v); e != nil {
+		return e
+	}
+	*p = Placement(v)
+	return nil
+}
+
+// OpacityMask returns a uniform mask that applies the placement's opacity
+// when used with draw.DrawMask. An opacity of 0 is treated as unset (opaque),
+// as placements written before opacity was applied store 0.
+func (p *Placement) OpacityMask() image.Image {
+	opacity := math.Max(0, math.Min(1, p.Opacity))
+	if p.Opacity == 0 {
+		opacity = 1
+	}
+	return image.NewUniform(color.Alpha16{A: uint16(opacity * 0xffff)})
 }
@@ -1,8 +1,65 @@
 package layer
 
+import (
+	"encoding/json"
+	"image"
+	"image/draw"
+)
+
+// Transformation represents a transformation of an image relative to its
+// original placement. Scaling and rotation is about the center of the image.
 type Transformation struct {
-	ShiftX uint16  `json:"shift_x"`
-	ShiftY uint16  `json:"shift_y"`
-	ScaleX float32 `json:"scale_x"`
-	ScaleY float32 `json:"scale_y"`
+	ShiftX int16   `json:"shift_x"` // x shift in pixels
+	ShiftY int16   `json:"shift_y"` // y shift in pixels
+	ScaleX float32 `json:"scale_x"` // x scale factor (defaults to 1)
+	ScaleY float32 `json:"scale_y"` // y scale factor (defaults to 1)
+	Rotate float32 `json:"rotate"`  // clockwise rotation in radians
+}
+
+// UnmarshalJSON ensures omitted scale factors default to 1.
+func (t *Transformation) UnmarshalJSON(data []byte) error {
+	type transformation Transformation
+	v := transformation{ScaleX: 1, ScaleY: 1}
+	if e := json.Unmarshal(data, &v); e != nil {
+		return e
+	}
+	*t = Transformation(v)
+	return nil
+}
+
+// IsIdentity determines whether the transformation leaves images unchanged.
+func (t *Transformation) IsIdentity() bool {
+	return t.ShiftX == 0 && t.ShiftY == 0 &&
+		t.ScaleX == 1 && t.ScaleY == 1 && t.Rotate == 0
+}
+
+// Transform applies the transformation to the image, keeping the bounds.
+func Transform(src image.Image, t *Transformation) (image.Image, error) {
+	if t.IsIdentity() {
+		return src, nil
+	}
+	var (
+		dst = image.NewRGBA(src.Bounds())
+		img = src
+		e   error
+	)
+	if t.ScaleX != 1 || t.ScaleY != 1 {
+		if img, e = Scale(img, float64(t.ScaleX), float64(t.ScaleY)); e != nil {
+			return nil, e
+		}
+	}
+	if t.Rotate != 0 {
+		if img, e = Rotate(img, float64(t.Rotate)); e != nil {
+			return nil, e
+		}
+	}
+	var (
+		srcBounds = src.Bounds()
+		imgBounds = img.Bounds()
+		spX       = (getRectWidth(srcBounds)-getRectWidth(imgBounds))/2 + int(t.ShiftX)
+		spY       = (getRectHeight(srcBounds)-getRectHeight(imgBounds))/2 + int(t.ShiftY)
+	)
+	draw.Draw(dst, imgBounds.Sub(imgBounds.Min).Add(srcBounds.Min).Add(image.Pt(spX, spY)),
+		img, imgBounds.Min, draw.Src)
+	return dst, nil
 }
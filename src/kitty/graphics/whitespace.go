@@ -36,11 +36,12 @@ func RemoveWhitespace(src image.Image) (image.Image, *Placement) {
 	draw.Draw(dst, dst.Bounds(), src, image.Pt(minX, minY), draw.Over)
 
 	return dst, &Placement{
-		CoordX: uint64((minX + maxX) / 2),
-		CoordY: uint64((minY + maxY) / 2),
-		ScaleX: 1,
-		ScaleY: 1,
-		Rotate: 0,
+		CoordX:  uint64((minX + maxX) / 2),
+		CoordY:  uint64((minY + maxY) / 2),
+		ScaleX:  1,
+		ScaleY:  1,
+		Rotate:  0,
+		Opacity: 1,
 	}
 }
 
@@ -63,11 +64,28 @@ func IncludeWhitespace(src image.Image, bounds image.Rectangle, at *Placement) (
 		spX      = int(at.CoordX) - getRectWidth(srcBound)/2
 		spY      = int(at.CoordY) - getRectHeight(srcBound)/2
 	)
-	draw.Draw(dst, bounds, src, image.Pt(-spX, -spY), draw.Src)
+	draw.DrawMask(dst, bounds, src, image.Pt(-spX, -spY), at.OpacityMask(), image.ZP, draw.Src)
 
 	return dst, nil
 }
 
+// OpaqueBounds returns the smallest rectangle containing all non-transparent
+// pixels of the image (empty if there are none).
+func OpaqueBounds(src image.Image) image.Rectangle {
+	var (
+		b   = src.Bounds()
+		out image.Rectangle
+	)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if _, _, _, a := src.At(x, y).RGBA(); a > 0 {
+				out = out.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return out
+}
+
 func getRectWidth(rectangle image.Rectangle) int {
 	return rectangle.Max.X - rectangle.Min.X
 }
@@ -0,0 +1,41 @@
+package layer
+
+import (
+	"encoding/json"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestIncludeWhitespace_Opacity(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for x := 0; x < 10; x++ {
+		for y := 0; y < 10; y++ {
+			src.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+	cases := []struct {
+		in  string
+		exp uint8
+	}{
+		{`{"coord_x":50,"coord_y":50,"scale_x":1,"scale_y":1}`, 255},
+		{`{"coord_x":50,"coord_y":50,"scale_x":1,"scale_y":1,"opacity":1}`, 255},
+		{`{"coord_x":50,"coord_y":50,"scale_x":1,"scale_y":1,"opacity":0.5}`, 127},
+		{`{"coord_x":50,"coord_y":50,"scale_x":1,"scale_y":1,"opacity":0.01}`, 2},
+		// Placements written before opacity was applied store an opacity of 0.
+		{`{"coord_x":50,"coord_y":50,"scale_x":1,"scale_y":1,"rotate":0,"opacity":0}`, 255},
+	}
+	for i, c := range cases {
+		var p Placement
+		if e := json.Unmarshal([]byte(c.in), &p); e != nil {
+			t.Fatal(e)
+		}
+		dst, e := IncludeWhitespace(src, image.Rect(0, 0, 100, 100), &p)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if got := color.RGBAModel.Convert(dst.At(50, 50)).(color.RGBA).A; got != c.exp {
+			t.Errorf("[%d] in(%s) expected alpha(%d) got(%d)", i, c.in, c.exp, got)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package scene
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/kittycash/kittiverse/src/kitty/genetics"
+	"github.com/kittycash/kittiverse/src/kitty/graphics"
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+var (
+	ErrInvalidSize       = errors.New("scene width and height must be positive")
+	ErrInvalidBackground = errors.New("background must have exactly one of 'color', 'gradient' or 'image'")
+)
+
+// Renderer generates kitties and obtains background images
+// (implemented by generator.Instance).
+type Renderer interface {
+	GenerateKitty(dna genetics.DNA) (image.Image, error)
+	GetBackground(name string) (image.Image, error)
+}
+
+// Config describes a scene of kitties placed on a background.
+type Config struct {
+	Width      int        `json:"width"`
+	Height     int        `json:"height"`
+	Background Background `json:"background"`
+	Kitties    []Kitty    `json:"kitties"` // drawn in order (last is on top).
+}
+
+// Background is either a solid color, a gradient, or a named background image
+// stored in the generation file (scaled to the size of the scene).
+type Background struct {
+	Color    string    `json:"color,omitempty"`    // hex color (rrggbb or rrggbbaa).
+	Gradient *Gradient `json:"gradient,omitempty"` // linear gradient.
+	Image    string    `json:"image,omitempty"`    // name of background image.
+}
+
+// Gradient is a linear gradient between two colors.
+type Gradient struct {
+	From       string `json:"from"`       // hex color of top (or left).
+	To         string `json:"to"`         // hex color of bottom (or right).
+	Horizontal bool   `json:"horizontal"` // whether gradient is left to right.
+}
+
+// Kitty is a kitty of given DNA placed in a scene. The coordinates of the
+// placement are of the center of the kitty image.
+type Kitty struct {
+	DNA       string          `json:"dna"`
+	Placement layer.Placement `json:"placement"`
+}
+
+// UnmarshalJSON ensures an omitted placement defaults to layer.NewPlacement.
+func (k *Kitty) UnmarshalJSON(data []byte) error {
+	type kitty Kitty
+	v := kitty{Placement: layer.NewPlacement()}
+	if e := json.Unmarshal(data, &v); e != nil {
+		return e
+	}
+	*k = Kitty(v)
+	return nil
+}
+
+// Render composes the scene.
+func Render(r Renderer, c *Config) (image.Image, error) {
+	if c.Width <= 0 || c.Height <= 0 {
+		return nil, ErrInvalidSize
+	}
+	bounds := image.Rect(0, 0, c.Width, c.Height)
+	dst, e := c.Background.generate(r, bounds)
+	if e != nil {
+		return nil, e
+	}
+	for i := range c.Kitties {
+		k := &c.Kitties[i]
+		dna, e := genetics.ParseDNA(k.DNA)
+		if e != nil {
+			return nil, e
+		}
+		img, e := r.GenerateKitty(dna)
+		if e != nil {
+			return nil, e
+		}
+		placed, e := layer.IncludeWhitespace(img, bounds, &k.Placement)
+		if e != nil {
+			return nil, e
+		}
+		draw.Draw(dst, bounds, placed, image.ZP, draw.Over)
+	}
+	return dst, nil
+}
+
+/*
+	<<< HELPERS >>>
+*/
+
+func (b *Background) generate(r Renderer, bounds image.Rectangle) (draw.Image, error) {
+	dst := image.NewRGBA(bounds)
+	switch {
+	case b.Color != "" && b.Gradient == nil && b.Image == "":
+		c, e := layer.ParseColor(b.Color)
+		if e != nil {
+			return nil, e
+		}
+		draw.Draw(dst, bounds, image.NewUniform(c), image.ZP, draw.Src)
+
+	case b.Color == "" && b.Gradient != nil && b.Image == "":
+		if e := b.Gradient.draw(dst); e != nil {
+			return nil, e
+		}
+
+	case b.Color == "" && b.Gradient == nil && b.Image != "":
+		img, e := r.GetBackground(b.Image)
+		if e != nil {
+			return nil, e
+		}
+		if img.Bounds().Size() != bounds.Size() {
+			sx := float64(bounds.Dx()) / float64(img.Bounds().Dx())
+			sy := float64(bounds.Dy()) / float64(img.Bounds().Dy())
+			if img, e = layer.Scale(img, sx, sy); e != nil {
+				return nil, e
+			}
+		}
+		draw.Draw(dst, bounds, img, img.Bounds().Min, draw.Src)
+
+	default:
+		return nil, ErrInvalidBackground
+	}
+	return dst, nil
+}
+
+func (g *Gradient) draw(dst *image.RGBA) error {
+	from, e := layer.ParseColor(g.From)
+	if e != nil {
+		return e
+	}
+	to, e := layer.ParseColor(g.To)
+	if e != nil {
+		return e
+	}
+	var (
+		b      = dst.Bounds()
+		steps  = b.Dy()
+		fromC  = color.NRGBAModel.Convert(from).(color.NRGBA)
+		toC    = color.NRGBAModel.Convert(to).(color.NRGBA)
+		interp = func(a, b uint8, t float64) uint8 {
+			return uint8(float64(a) + (float64(b)-float64(a))*t)
+		}
+	)
+	if g.Horizontal {
+		steps = b.Dx()
+	}
+	for i := 0; i < steps; i++ {
+		t := 0.0
+		if steps > 1 {
+			t = float64(i) / float64(steps-1)
+		}
+		c := image.NewUniform(color.NRGBA{
+			R: interp(fromC.R, toC.R, t),
+			G: interp(fromC.G, toC.G, t),
+			B: interp(fromC.B, toC.B, t),
+			A: interp(fromC.A, toC.A, t),
+		})
+		line := image.Rect(b.Min.X, b.Min.Y+i, b.Max.X, b.Min.Y+i+1)
+		if g.Horizontal {
+			line = image.Rect(b.Min.X+i, b.Min.Y, b.Min.X+i+1, b.Max.Y)
+		}
+		draw.Draw(dst, line, c, image.ZP, draw.Src)
+	}
+	return nil
+}
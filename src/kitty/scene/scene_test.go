@@ -0,0 +1,54 @@
+package scene
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/kittycash/kittiverse/src/kitty/genetics"
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+type testRenderer struct{}
+
+func (testRenderer) GenerateKitty(dna genetics.DNA) (image.Image, error) {
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{R: 255, A: 255}), image.ZP, draw.Src)
+	return img, nil
+}
+
+func (testRenderer) GetBackground(name string) (image.Image, error) {
+	return nil, nil
+}
+
+func TestRender_Placement(t *testing.T) {
+	cases := []struct {
+		name   string
+		kitty  string
+		at     image.Point
+		expRed bool
+	}{
+		{"no placement", `{"dna": "%s"}`, image.Pt(2, 2), true},
+		{"no scale or opacity", `{"dna": "%s", "placement": {"coord_x": 10, "coord_y": 10}}`, image.Pt(10, 10), true},
+		{"zero opacity is unset", `{"dna": "%s", "placement": {"coord_x": 10, "coord_y": 10, "opacity": 0}}`, image.Pt(10, 10), true},
+		{"low opacity", `{"dna": "%s", "placement": {"coord_x": 10, "coord_y": 10, "opacity": 0.01}}`, image.Pt(10, 10), false},
+	}
+	dna := genetics.DNA{}.Hex()
+	for _, c := range cases {
+		data := []byte(`{"width": 20, "height": 20, "background": {"color": "ffffff"}, "kitties": [` +
+			fmt.Sprintf(c.kitty, dna) + `]}`)
+		var config Config
+		if e := json.Unmarshal(data, &config); e != nil {
+			t.Fatalf("%s: %v", c.name, e)
+		}
+		img, e := Render(testRenderer{}, &config)
+		if e != nil {
+			t.Fatalf("%s: %v", c.name, e)
+		}
+		r, g, _, _ := img.At(c.at.X, c.at.Y).RGBA()
+		if isRed := r == 0xffff && g == 0; isRed != c.expRed {
+			t.Errorf("%s: expected kitty drawn at %v: %v, got %v", c.name, c.at, c.expRed, isRed)
+		}
+	}
+}
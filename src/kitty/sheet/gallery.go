@@ -0,0 +1,155 @@
+package sheet
+
+import (
+	"errors"
+	"github.com/kittycash/kittiverse/src/kitty/generator/container"
+	"github.com/kittycash/kittiverse/src/kitty/genetics"
+	"github.com/kittycash/kittiverse/src/kitty/graphics"
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+var (
+	// MissingColor highlights cells of breed and attribute combinations that
+	// have no layer.
+	MissingColor = color.NRGBA{R: 255, G: 200, B: 200, A: 255}
+
+	// FallbackColor labels cells that are rendered with the layer of another
+	// breed in the fallback chain.
+	FallbackColor = color.NRGBA{R: 128, G: 128, B: 128, A: 255}
+
+	// GridColor is the color of the lines between cells.
+	GridColor = color.NRGBA{R: 220, G: 220, B: 220, A: 255}
+)
+
+// GalleryRenderer generates kitties and describes the layers available to
+// render them (implemented by generator.Instance).
+type GalleryRenderer interface {
+	Renderer
+	GetBreeds() []string
+	GetAttributes(layerType string) ([]string, bool)
+	GetLayerStatus(layerType, breed, attribute string) container.LayerStatus
+	AttributeDNA(base genetics.DNA, layerType, breed, attribute string) (genetics.DNA, error)
+}
+
+// Gallery renders every attribute of the layer type (as columns) for every
+// breed (as rows) on the base kitty. Cells rendered with the layer of a
+// fallback breed are labelled, and combinations with no layer (or that fail
+// to render) are highlighted.
+func Gallery(r GalleryRenderer, layerType string, base genetics.DNA, cellSize, workers int) (image.Image, error) {
+	if cellSize <= 0 {
+		return nil, ErrInvalidLayout
+	}
+	attributes, ok := r.GetAttributes(layerType)
+	if !ok {
+		return nil, errors.New("unknown layer type: " + layerType)
+	}
+	breeds := r.GetBreeds()
+	if len(attributes) == 0 || len(breeds) == 0 {
+		return nil, ErrNoKitties
+	}
+
+	// Determine DNAs of the cells that can be rendered.
+	var (
+		statuses = make([]container.LayerStatus, len(breeds)*len(attributes))
+		dnas     []genetics.DNA
+		cells    []int
+	)
+	for b, breed := range breeds {
+		for a, attribute := range attributes {
+			i := b*len(attributes) + a
+			if statuses[i] = r.GetLayerStatus(layerType, breed, attribute); statuses[i] == container.LayerMissing {
+				continue
+			}
+			dna, e := r.AttributeDNA(base, layerType, breed, attribute)
+			if e != nil {
+				return nil, e
+			}
+			dnas = append(dnas, dna)
+			cells = append(cells, i)
+		}
+	}
+	imgs, errs := renderEach(r, dnas, workers, func(_ int, img image.Image) (image.Image, error) {
+		return fit(img, cellSize)
+	})
+
+	// Layout.
+	var (
+		lineHeight = layer.TextHeight(CaptionScale) + CaptionScale
+		labelW     = 0
+		headerH    = CaptionMaxLines*lineHeight + Padding
+	)
+	for _, breed := range breeds {
+		if w := layer.TextWidth(breed, CaptionScale); w > labelW {
+			labelW = w
+		}
+	}
+	labelW += 2 * Padding
+	var (
+		cellRect = func(i int) image.Rectangle {
+			x := labelW + (i%len(attributes))*cellSize
+			y := headerH + (i/len(attributes))*cellSize
+			return image.Rect(x, y, x+cellSize, y+cellSize)
+		}
+		dst = image.NewRGBA(image.Rect(0, 0,
+			labelW+len(attributes)*cellSize+1, headerH+len(breeds)*cellSize+1))
+	)
+
+	// Labels.
+	layer.DrawText(dst, image.Pt(Padding, Padding), layerType, CaptionScale, color.Black)
+	for a, attribute := range attributes {
+		lines := layer.WrapText(attribute, cellSize-Padding, CaptionScale)
+		if len(lines) > CaptionMaxLines-1 {
+			lines = lines[:CaptionMaxLines-1]
+		}
+		x := labelW + a*cellSize + Padding/2
+		for j, line := range lines {
+			layer.DrawText(dst, image.Pt(x, headerH-(len(lines)-j)*lineHeight), line, CaptionScale, color.Black)
+		}
+	}
+	for b, breed := range breeds {
+		at := image.Pt(Padding, headerH+b*cellSize+(cellSize-lineHeight)/2)
+		layer.DrawText(dst, at, breed, CaptionScale, color.Black)
+	}
+
+	// Cells.
+	highlight := func(rect image.Rectangle, text string) {
+		draw.Draw(dst, rect, image.NewUniform(MissingColor), image.ZP, draw.Src)
+		layer.DrawText(dst, rect.Min.Add(image.Pt(Padding/2, Padding/2)),
+			text, CaptionScale, color.Black)
+	}
+	for i, status := range statuses {
+		if status == container.LayerMissing {
+			highlight(cellRect(i), "missing")
+		}
+	}
+	for j, img := range imgs {
+		rect := cellRect(cells[j])
+		if errs[j] != nil {
+			highlight(rect, "failed")
+			continue
+		}
+		offset := image.Pt(
+			(cellSize-img.Bounds().Dx())/2,
+			(cellSize-img.Bounds().Dy())/2)
+		draw.Draw(dst, image.Rectangle{Max: img.Bounds().Size()}.Add(rect.Min.Add(offset)),
+			img, img.Bounds().Min, draw.Over)
+		if statuses[cells[j]] == container.LayerFallback {
+			layer.DrawText(dst, rect.Min.Add(image.Pt(Padding/2, Padding/2)),
+				"fallback", CaptionScale, FallbackColor)
+		}
+	}
+
+	// Grid lines.
+	grid := image.NewUniform(GridColor)
+	for a := 0; a <= len(attributes); a++ {
+		x := labelW + a*cellSize
+		draw.Draw(dst, image.Rect(x, headerH, x+1, dst.Bounds().Max.Y), grid, image.ZP, draw.Src)
+	}
+	for b := 0; b <= len(breeds); b++ {
+		y := headerH + b*cellSize
+		draw.Draw(dst, image.Rect(labelW, y, dst.Bounds().Max.X, y+1), grid, image.ZP, draw.Src)
+	}
+	return dst, nil
+}
@@ -0,0 +1,107 @@
+package sheet
+
+import (
+	"errors"
+	"github.com/kittycash/kittiverse/src/kitty/generator/container"
+	"github.com/kittycash/kittiverse/src/kitty/genetics"
+	"github.com/kittycash/kittiverse/src/kitty/graphics"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// testGalleryRenderer has attributes "x" and "y" of layer type "eyes", of
+// which "y" is missing for breed "b".
+type testGalleryRenderer struct {
+	testRenderer
+}
+
+func (r *testGalleryRenderer) GetBreeds() []string {
+	return []string{"default", "b"}
+}
+
+func (r *testGalleryRenderer) GetAttributes(layerType string) ([]string, bool) {
+	if layerType != "eyes" {
+		return nil, false
+	}
+	return []string{"x", "y"}, true
+}
+
+func (r *testGalleryRenderer) GetLayerStatus(layerType, breed, attribute string) container.LayerStatus {
+	switch {
+	case breed == "default":
+		return container.LayerOwn
+	case attribute == "x":
+		return container.LayerFallback
+	default:
+		return container.LayerMissing
+	}
+}
+
+func (r *testGalleryRenderer) AttributeDNA(base genetics.DNA, layerType, breed, attribute string) (genetics.DNA, error) {
+	if r.GetLayerStatus(layerType, breed, attribute) == container.LayerMissing {
+		return base, errors.New("missing layer")
+	}
+	i := 0
+	if breed != "default" {
+		i += 2
+	}
+	if attribute != "x" {
+		i++
+	}
+	return testDNA(i), nil
+}
+
+func TestGallery(t *testing.T) {
+	const cellSize = 50
+	r := &testGalleryRenderer{testRenderer{rects: map[genetics.DNA]image.Rectangle{
+		testDNA(0): image.Rect(0, 0, 100, 100),
+		testDNA(2): image.Rect(0, 0, 100, 100),
+		// DNA of attribute "y" of breed "default" fails to render.
+	}}}
+	img, e := Gallery(r, "eyes", genetics.DNA{}, cellSize, 2)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	var (
+		labelW  = layer.TextWidth("default", CaptionScale) + 2*Padding
+		headerH = CaptionMaxLines*(layer.TextHeight(CaptionScale)+CaptionScale) + Padding
+		expSize = image.Pt(labelW+2*cellSize+1, headerH+2*cellSize+1)
+	)
+	if size := img.Bounds().Size(); size != expSize {
+		t.Fatalf("expected size %v, got %v", expSize, size)
+	}
+	red := color.RGBA{R: 255, A: 255}
+	cases := []struct {
+		breed     string
+		attribute string
+		row, col  int
+		exp       color.Color
+	}{
+		{"default", "x", 0, 0, red},
+		{"default", "y", 0, 1, MissingColor}, // failed.
+		{"b", "x", 1, 0, red},                // fallback.
+		{"b", "y", 1, 1, MissingColor},       // missing.
+	}
+	for _, c := range cases {
+		center := image.Pt(labelW+c.col*cellSize+cellSize/2, headerH+c.row*cellSize+cellSize/2)
+		if !sameColor(img.At(center.X, center.Y), c.exp) {
+			t.Errorf("%s of %s: expected cell color %v, got %v",
+				c.attribute, c.breed, c.exp, img.At(center.X, center.Y))
+		}
+	}
+
+	if _, e := Gallery(r, "ears", genetics.DNA{}, cellSize, 0); e == nil {
+		t.Error("expected error of unknown layer type")
+	}
+	if _, e := Gallery(r, "eyes", genetics.DNA{}, 0, 0); e != ErrInvalidLayout {
+		t.Errorf("expected %v, got %v", ErrInvalidLayout, e)
+	}
+}
+
+func sameColor(a, b color.Color) bool {
+	ar, ag, ab, aa := a.RGBA()
+	br, bg, bb, ba := b.RGBA()
+	return ar == br && ag == bg && ab == bb && aa == ba
+}
@@ -0,0 +1,298 @@
+package sheet
+
+import (
+	"errors"
+	"github.com/kittycash/kittiverse/src/kitty/generator/container"
+	"github.com/kittycash/kittiverse/src/kitty/genetics"
+	"github.com/kittycash/kittiverse/src/kitty/graphics"
+	"image"
+	"image/color"
+	"image/draw"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// CaptionScale is the scale of the caption font.
+	CaptionScale = 2
+
+	// CaptionMaxLines is the maximum number of caption lines below a cell.
+	CaptionMaxLines = 4
+
+	// Padding is the space in pixels between cells and captions.
+	Padding = 8
+)
+
+var (
+	ErrNoKitties     = errors.New("no kitties to render")
+	ErrInvalidLayout = errors.New("columns and cell size must be positive")
+)
+
+// Renderer generates kitties (implemented by generator.Instance).
+type Renderer interface {
+	GenerateKitty(dna genetics.DNA) (image.Image, error)
+}
+
+// RenderAll generates the kitties of the given DNAs in parallel, using the
+// given number of workers (the number of CPUs if not positive). The returned
+// images are in the same order as the DNAs.
+func RenderAll(r Renderer, dnas []genetics.DNA, workers int) ([]image.Image, error) {
+	return firstError(renderEach(r, dnas, workers, nil))
+}
+
+// RenderFitted is as RenderAll, but each kitty is scaled down to fit a square
+// of given size as soon as it is generated (so that only the scaled images
+// are held in memory). The images are suitable for Sheet of the same size.
+func RenderFitted(r Renderer, dnas []genetics.DNA, size, workers int) ([]image.Image, error) {
+	if size <= 0 {
+		return nil, ErrInvalidLayout
+	}
+	return firstError(renderEach(r, dnas, workers, func(_ int, img image.Image) (image.Image, error) {
+		return fit(img, size)
+	}))
+}
+
+// Sheet arranges the images into a grid of given number of columns, in which
+// each image is scaled to fit a square cell of given size. If captions are
+// provided, they are wrapped and drawn below the respective cells.
+func Sheet(imgs []image.Image, captions []string, columns, cellSize int) (image.Image, error) {
+	if len(imgs) == 0 {
+		return nil, ErrNoKitties
+	}
+	if columns <= 0 || cellSize <= 0 {
+		return nil, ErrInvalidLayout
+	}
+	if columns > len(imgs) {
+		columns = len(imgs)
+	}
+	var (
+		rows        = (len(imgs) + columns - 1) / columns
+		lineHeight  = layer.TextHeight(CaptionScale) + CaptionScale
+		captionSize int
+	)
+	if len(captions) > 0 {
+		captionSize = CaptionMaxLines*lineHeight + Padding
+	}
+	var (
+		cellW = cellSize + Padding
+		cellH = cellSize + captionSize + Padding
+		dst   = image.NewRGBA(image.Rect(0, 0, columns*cellW+Padding, rows*cellH+Padding))
+	)
+	for i, img := range imgs {
+		at := image.Pt(Padding+(i%columns)*cellW, Padding+(i/columns)*cellH)
+
+		scaled, e := fit(img, cellSize)
+		if e != nil {
+			return nil, e
+		}
+		draw.Draw(dst, image.Rectangle{Max: scaled.Bounds().Size()}.Add(at), scaled, scaled.Bounds().Min, draw.Over)
+
+		if i >= len(captions) {
+			continue
+		}
+		lines := layer.WrapText(captions[i], cellSize, CaptionScale)
+		if len(lines) > CaptionMaxLines {
+			lines = lines[:CaptionMaxLines]
+		}
+		for j, line := range lines {
+			layer.DrawText(dst,
+				at.Add(image.Pt(0, cellSize+Padding+j*lineHeight)),
+				line, CaptionScale, color.Black)
+		}
+	}
+	return dst, nil
+}
+
+// HexCaption returns the first n characters of the hex representation of DNA.
+func HexCaption(dna genetics.DNA, n int) string {
+	h := dna.Hex()
+	if n > 0 && n < len(h) {
+		h = h[:n]
+	}
+	return h
+}
+
+// AttributeCaption joins the breed and attribute names of the phenotype into
+// a caption.
+func AttributeCaption(p *container.Phenotype) string {
+	names := []string{p.Breed}
+	for _, t := range p.Traits {
+		names = append(names, t.Attribute)
+	}
+	return strings.Join(names, " ")
+}
+
+// AtlasEntry is the location of a kitty within an atlas image.
+type AtlasEntry struct {
+	DNA     genetics.DNA `json:"dna"`
+	X       int          `json:"x"`
+	Y       int          `json:"y"`
+	Width   int          `json:"width"`
+	Height  int          `json:"height"`
+	OffsetX int          `json:"offset_x"` // position of the trimmed image within
+	OffsetY int          `json:"offset_y"` // the original kitty image.
+}
+
+// Atlas packs the images (with transparent whitespace trimmed) into a single
+// image no wider than maxWidth, using shelf packing of images sorted by
+// height. It returns the atlas, and an entry for each image (in the order of
+// the given images).
+func Atlas(imgs []image.Image, dnas []genetics.DNA, maxWidth int) (image.Image, []AtlasEntry, error) {
+	ts := make([]trimmed, len(imgs))
+	for i, img := range imgs {
+		b := layer.OpaqueBounds(img)
+		ts[i] = trimmed{img: img, bounds: b, offset: b.Min.Sub(img.Bounds().Min)}
+	}
+	return pack(ts, dnas, maxWidth)
+}
+
+// RenderAtlas generates the kitties in parallel (as RenderAll) and packs them
+// into an atlas (as Atlas). Each kitty is trimmed as soon as it is generated,
+// so that only the trimmed images are held in memory.
+func RenderAtlas(r Renderer, dnas []genetics.DNA, maxWidth, workers int) (image.Image, []AtlasEntry, error) {
+	offsets := make([]image.Point, len(dnas))
+	imgs, e := firstError(renderEach(r, dnas, workers, func(i int, img image.Image) (image.Image, error) {
+		var trim image.Image
+		trim, offsets[i] = trimCopy(img)
+		return trim, nil
+	}))
+	if e != nil {
+		return nil, nil, e
+	}
+	ts := make([]trimmed, len(imgs))
+	for i, img := range imgs {
+		ts[i] = trimmed{img: img, bounds: img.Bounds(), offset: offsets[i]}
+	}
+	return pack(ts, dnas, maxWidth)
+}
+
+/*
+	<<< HELPERS >>>
+*/
+
+// trimmed is an image of which only the opaque bounds are packed into an
+// atlas.
+type trimmed struct {
+	img    image.Image
+	bounds image.Rectangle // opaque area of img.
+	offset image.Point     // position of the opaque area within the original kitty image.
+}
+
+// pack packs the trimmed images into an atlas (see Atlas).
+func pack(ts []trimmed, dnas []genetics.DNA, maxWidth int) (image.Image, []AtlasEntry, error) {
+	if len(ts) == 0 {
+		return nil, nil, ErrNoKitties
+	}
+	var (
+		entries = make([]AtlasEntry, len(ts))
+		order   = make([]int, len(ts))
+	)
+	for i, t := range ts {
+		if w := t.bounds.Dx(); w > maxWidth {
+			maxWidth = w
+		}
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return ts[order[a]].bounds.Dy() > ts[order[b]].bounds.Dy()
+	})
+
+	var x, y, shelfHeight, width int
+	for _, i := range order {
+		w, h := ts[i].bounds.Dx(), ts[i].bounds.Dy()
+		if x+w > maxWidth {
+			x, y, shelfHeight = 0, y+shelfHeight, 0
+		}
+		entries[i] = AtlasEntry{
+			DNA:     dnas[i],
+			X:       x,
+			Y:       y,
+			Width:   w,
+			Height:  h,
+			OffsetX: ts[i].offset.X,
+			OffsetY: ts[i].offset.Y,
+		}
+		if x += w; x > width {
+			width = x
+		}
+		if h > shelfHeight {
+			shelfHeight = h
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, y+shelfHeight))
+	for i, t := range ts {
+		e := entries[i]
+		draw.Draw(dst, image.Rect(e.X, e.Y, e.X+e.Width, e.Y+e.Height), t.img, t.bounds.Min, draw.Src)
+	}
+	return dst, entries, nil
+}
+
+// renderEach generates the kitties in parallel, returning the image and error
+// of each DNA. If given, process is applied to each generated image by the
+// worker that generated it, and its result is kept instead.
+func renderEach(r Renderer, dnas []genetics.DNA, workers int,
+	process func(i int, img image.Image) (image.Image, error)) ([]image.Image, []error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	var (
+		imgs  = make([]image.Image, len(dnas))
+		errs  = make([]error, len(dnas))
+		jobs  = make(chan int)
+		group sync.WaitGroup
+	)
+	for w := 0; w < workers; w++ {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			for i := range jobs {
+				img, e := r.GenerateKitty(dnas[i])
+				if e == nil && process != nil {
+					img, e = process(i, img)
+				}
+				imgs[i], errs[i] = img, e
+			}
+		}()
+	}
+	for i := range dnas {
+		jobs <- i
+	}
+	close(jobs)
+	group.Wait()
+	return imgs, errs
+}
+
+// firstError returns the images, or the first of the errors.
+func firstError(imgs []image.Image, errs []error) ([]image.Image, error) {
+	for _, e := range errs {
+		if e != nil {
+			return nil, e
+		}
+	}
+	return imgs, nil
+}
+
+// trimCopy copies the opaque area of the image into a new image, returning it
+// and the position of the area within the image.
+func trimCopy(img image.Image) (image.Image, image.Point) {
+	b := layer.OpaqueBounds(img)
+	dst := image.NewRGBA(image.Rectangle{Max: b.Size()})
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+	return dst, b.Min.Sub(img.Bounds().Min)
+}
+
+// fit scales the image down (keeping aspect ratio) to fit a square of size.
+func fit(img image.Image, size int) (image.Image, error) {
+	b := img.Bounds()
+	if b.Dx() <= size && b.Dy() <= size {
+		return img, nil
+	}
+	s := float64(size) / float64(b.Dx())
+	if b.Dy() > b.Dx() {
+		s = float64(size) / float64(b.Dy())
+	}
+	return layer.Scale(img, s, s)
+}
@@ -0,0 +1,166 @@
+package sheet
+
+import (
+	"errors"
+	"github.com/kittycash/kittiverse/src/kitty/genetics"
+	"github.com/kittycash/kittiverse/src/kitty/graphics"
+	"image"
+	"image/color"
+	"image/draw"
+	"reflect"
+	"testing"
+)
+
+// testRenderer generates kitties of size 100x100, which are opaque within the
+// rectangle of the kitty's DNA (see testDNA).
+type testRenderer struct {
+	rects map[genetics.DNA]image.Rectangle
+}
+
+func (r *testRenderer) GenerateKitty(dna genetics.DNA) (image.Image, error) {
+	rect, ok := r.rects[dna]
+	if !ok {
+		return nil, errors.New("unknown DNA")
+	}
+	return testImage(rect), nil
+}
+
+func testImage(rect image.Rectangle) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	draw.Draw(img, rect, image.NewUniform(color.RGBA{R: 255, A: 255}), image.ZP, draw.Src)
+	return img
+}
+
+func testDNA(i int) genetics.DNA {
+	var dna genetics.DNA
+	dna.SetVersion(genetics.DNAVersion)
+	dna.SetGenotype(genetics.DNABodyAttrPos, genetics.Allele{}, genetics.Allele{},
+		genetics.NewAlleleFromUint16(uint16(i)))
+	return dna
+}
+
+func TestAtlas(t *testing.T) {
+	rects := []image.Rectangle{
+		image.Rect(10, 20, 50, 40),  // 40x20
+		image.Rect(0, 0, 30, 60),    // 30x60
+		image.Rect(50, 50, 100, 90), // 50x40
+		image.Rect(5, 5, 25, 15),    // 20x10
+	}
+	var (
+		imgs = make([]image.Image, len(rects))
+		dnas = make([]genetics.DNA, len(rects))
+		r    = &testRenderer{rects: make(map[genetics.DNA]image.Rectangle)}
+	)
+	for i, rect := range rects {
+		imgs[i], dnas[i] = testImage(rect), testDNA(i)
+		r.rects[dnas[i]] = rect
+	}
+
+	// Shelves of images sorted by height: [30x60, 50x40], [40x20, 20x10].
+	exp := []AtlasEntry{
+		{X: 0, Y: 60, Width: 40, Height: 20, OffsetX: 10, OffsetY: 20},
+		{X: 0, Y: 0, Width: 30, Height: 60, OffsetX: 0, OffsetY: 0},
+		{X: 30, Y: 0, Width: 50, Height: 40, OffsetX: 50, OffsetY: 50},
+		{X: 40, Y: 60, Width: 20, Height: 10, OffsetX: 5, OffsetY: 5},
+	}
+	for i := range exp {
+		exp[i].DNA = dnas[i]
+	}
+
+	atlas, entries, e := Atlas(imgs, dnas, 90)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if !reflect.DeepEqual(entries, exp) {
+		t.Errorf("expected entries %+v, got %+v", exp, entries)
+	}
+	if b := atlas.Bounds(); b != image.Rect(0, 0, 80, 80) {
+		t.Errorf("expected atlas bounds %v, got %v", image.Rect(0, 0, 80, 80), b)
+	}
+	// Each entry is an opaque copy of the trimmed kitty.
+	for i, entry := range entries {
+		rect := image.Rect(entry.X, entry.Y, entry.X+entry.Width, entry.Y+entry.Height)
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			for x := rect.Min.X; x < rect.Max.X; x++ {
+				if _, _, _, a := atlas.At(x, y).RGBA(); a == 0 {
+					t.Fatalf("entry %d: transparent pixel at (%d, %d)", i, x, y)
+				}
+			}
+		}
+	}
+
+	// Rendering and trimming within workers packs the same atlas.
+	rendered, renderedEntries, e := RenderAtlas(r, dnas, 90, 2)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if !reflect.DeepEqual(renderedEntries, entries) {
+		t.Errorf("expected rendered entries %+v, got %+v", entries, renderedEntries)
+	}
+	if !reflect.DeepEqual(rendered.(*image.RGBA).Pix, atlas.(*image.RGBA).Pix) {
+		t.Error("expected rendered atlas to be the same")
+	}
+
+	// Images wider than the maximum width widen the atlas.
+	if _, entries, _ := Atlas(imgs[2:3], dnas[2:3], 10); entries[0].Width != 50 {
+		t.Errorf("expected entry of width 50, got %d", entries[0].Width)
+	}
+	if _, _, e := Atlas(nil, nil, 90); e != ErrNoKitties {
+		t.Errorf("expected %v, got %v", ErrNoKitties, e)
+	}
+	if _, _, e := RenderAtlas(r, []genetics.DNA{testDNA(9)}, 90, 1); e == nil {
+		t.Error("expected error of unknown DNA")
+	}
+}
+
+func TestSheet(t *testing.T) {
+	var (
+		r    = &testRenderer{rects: make(map[genetics.DNA]image.Rectangle)}
+		dnas = make([]genetics.DNA, 5)
+	)
+	for i := range dnas {
+		dnas[i] = testDNA(i)
+		r.rects[dnas[i]] = image.Rect(0, 0, 100, 100)
+	}
+	imgs, e := RenderFitted(r, dnas, 50, 0)
+	if e != nil {
+		t.Fatal(e)
+	}
+	for i, img := range imgs {
+		if b := img.Bounds(); b.Dx() != 50 || b.Dy() != 50 {
+			t.Errorf("image %d: expected to be fitted to 50x50, got %v", i, b)
+		}
+	}
+
+	cases := []struct {
+		columns  int
+		captions []string
+		expW     int
+		expH     int
+	}{
+		{2, nil, 2*(50+Padding) + Padding, 3*(50+Padding) + Padding},
+		{5, nil, 5*(50+Padding) + Padding, 50 + 2*Padding},
+		{9, nil, 5*(50+Padding) + Padding, 50 + 2*Padding}, // no more columns than images.
+		{5, []string{"a"}, 5*(50+Padding) + Padding,
+			50 + CaptionMaxLines*(layer.TextHeight(CaptionScale)+CaptionScale) + 3*Padding},
+	}
+	for _, c := range cases {
+		img, e := Sheet(imgs, c.captions, c.columns, 50)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if b := img.Bounds(); b.Dx() != c.expW || b.Dy() != c.expH {
+			t.Errorf("%d columns: expected %dx%d, got %dx%d", c.columns, c.expW, c.expH, b.Dx(), b.Dy())
+		}
+	}
+
+	if _, e := Sheet(imgs, nil, 0, 50); e != ErrInvalidLayout {
+		t.Errorf("expected %v, got %v", ErrInvalidLayout, e)
+	}
+	if _, e := Sheet(nil, nil, 2, 50); e != ErrNoKitties {
+		t.Errorf("expected %v, got %v", ErrNoKitties, e)
+	}
+	if _, e := RenderFitted(r, dnas, 0, 0); e != ErrInvalidLayout {
+		t.Errorf("expected %v, got %v", ErrInvalidLayout, e)
+	}
+}